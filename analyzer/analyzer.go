@@ -0,0 +1,950 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// NewPtrAnalyzer returns an analyzer configured through its Flags, for use
+// with drivers such as singlechecker that parse analyzer flags.
+func NewPtrAnalyzer() *analysis.Analyzer {
+	c := &checker{}
+	ptrAnalyzer := c.analyzer()
+	c.opts.RegisterFlags(&ptrAnalyzer.Flags)
+	return ptrAnalyzer
+}
+
+// NewPtrAnalyzerWithOptions returns an analyzer with a fixed configuration,
+// for embedding in multichecker binaries or other programs.
+func NewPtrAnalyzerWithOptions(opts Options) *analysis.Analyzer {
+	c := &checker{opts: opts}
+	return c.analyzer()
+}
+
+// checker holds the options of a single analyzer instance, so differently
+// configured analyzers can run side by side.
+type checker struct {
+	opts Options
+}
+
+func (c *checker) analyzer() *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:     "ptrcmp",
+		Doc:      "checks that there are no pointer comparisons between basic types",
+		Requires: []*analysis.Analyzer{inspect.Analyzer},
+		Run:      c.run,
+	}
+}
+
+func (c *checker) run(pass *analysis.Pass) (any, error) {
+	opts := &c.opts
+	kinds, err := opts.kindSet()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFixMode(opts.FixMode); err != nil {
+		return nil, err
+	}
+	if err := opts.validateNilScopes(); err != nil {
+		return nil, err
+	}
+	if err := validateIdentityHelper(opts.IdentityHelper); err != nil {
+		return nil, err
+	}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.BinaryExpr)(nil),
+	}
+	disabled := make(map[*ast.File][]span)
+	boxed := make(map[*ast.File]map[*types.Var]types.Type)
+	commented := make(map[token.Pos]bool)
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		binaryExpr, ok := n.(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+		if !opts.CheckTests && strings.HasSuffix(pass.Fset.Position(binaryExpr.Pos()).Filename, "_test.go") {
+			c.trace(pass, binaryExpr, nil, "test file, see -check-tests")
+			return true
+		}
+		file := stack[0].(*ast.File)
+		if _, ok := disabled[file]; !ok {
+			disabled[file] = disabledSpans(file)
+		}
+		if inSpans(disabled[file], binaryExpr.Pos()) {
+			c.trace(pass, binaryExpr, nil, "disabled by "+disableDirective)
+			return true
+		}
+		if opts.ExportedOnly && !inExportedFunction(stack) {
+			c.trace(pass, binaryExpr, nil, "outside exported functions, see -exported-only")
+			return true
+		}
+
+		fileBoxedVars := func() map[*types.Var]types.Type {
+			if _, ok := boxed[file]; !ok {
+				boxed[file] = boxedVars(pass, file)
+			}
+			return boxed[file]
+		}
+		d, reason := c.check(pass, binaryExpr, stack, kinds, fileBoxedVars)
+		if d != nil && opts.FixMode == FixComment && opts.IdentityHelper == "" && len(d.SuggestedFixes) > 0 {
+			// One TODO per line: later comparisons on it would repeat the insertion.
+			line := d.SuggestedFixes[0].TextEdits[0].Pos
+			if commented[line] {
+				d.SuggestedFixes = nil
+			}
+			commented[line] = true
+		}
+		if d != nil {
+			pass.Report(*d)
+		}
+		c.trace(pass, binaryExpr, d, reason)
+		return true
+	})
+	return nil, nil
+}
+
+// check returns the diagnostic to report for the binary expression at the
+// top of stack, or nil and the reason it isn't reported.
+func (c *checker) check(pass *analysis.Pass, binaryExpr *ast.BinaryExpr, stack []ast.Node, kinds map[types.BasicKind]bool, boxedVars func() map[*types.Var]types.Type) (*analysis.Diagnostic, string) {
+	opts := &c.opts
+
+	// Go doesn't allow ordering pointers, so an ordered comparison of
+	// pointers is only seen in code that fails to type-check, usually a
+	// comparison of the values missing its dereferences. Ordering addresses
+	// through uintptr is valid. Both are only checked by the ordered rule.
+	var ordered bool
+	switch binaryExpr.Op {
+	case token.EQL, token.NEQ:
+	case token.LSS, token.GTR, token.LEQ, token.GEQ:
+		ordered = true
+	default:
+		return nil, "not a comparison"
+	}
+	// with and using are inserted into messages to name the operator, using
+	// where the message already says "with nil".
+	var with, using string
+	if opts.ReportOperator {
+		with = fmt.Sprintf(" with '%s'", binaryExpr.Op)
+		using = fmt.Sprintf(" using '%s'", binaryExpr.Op)
+	}
+
+	if elemType, ok := identityComparison(pass, binaryExpr, opts.IdentityTypes); ok && !ordered {
+		if !opts.enabled("identity") {
+			return nil, "rule identity is disabled"
+		}
+		return &analysis.Diagnostic{
+			Pos:      binaryExpr.Pos(),
+			End:      binaryExpr.End(),
+			Category: "identity",
+			Message:  fmt.Sprintf("comparing the values behind pointers to identity type %v%s; did you mean to compare the pointers?", elemType, with),
+			Related:  operandDeclarations(pass, ast.Unparen(binaryExpr.X).(*ast.StarExpr).X, ast.Unparen(binaryExpr.Y).(*ast.StarExpr).X),
+		}, ""
+	}
+
+	if elemType, ok := nilComparison(pass, binaryExpr); ok && !ordered {
+		if !opts.enabled("nil") {
+			return nil, "rule nil is disabled"
+		}
+		if !opts.nilInScope(stack) {
+			return nil, "outside the functions of -flag-nil-in"
+		}
+		if !opts.reportable(elemType, kinds) {
+			return nil, excluded(elemType)
+		}
+		return &analysis.Diagnostic{
+			Pos:      binaryExpr.Pos(),
+			End:      binaryExpr.End(),
+			Category: "nil",
+			Message:  fmt.Sprintf("comparing pointer to basic type with nil%s: %v", using, elemType),
+			Related:  operandDeclarations(pass, binaryExpr.X, binaryExpr.Y),
+		}, ""
+	}
+
+	if left, right, ok := uintptrComparison(pass, binaryExpr); ok {
+		switch {
+		case ordered && !opts.enabled("ordered"):
+			return nil, "rule ordered is disabled"
+		case !opts.enabled("unsafe"):
+			return nil, "rule unsafe is disabled"
+		case !opts.reportable(left, kinds):
+			return nil, excluded(left)
+		case !opts.reportable(right, kinds):
+			return nil, excluded(right)
+		}
+		return &analysis.Diagnostic{
+			Pos:      binaryExpr.Pos(),
+			End:      binaryExpr.End(),
+			Category: "unsafe",
+			Message:  fmt.Sprintf("comparing addresses of pointers to basic types through uintptr%s: %v and %v", with, left, right),
+			Related:  operandDeclarations(pass, binaryExpr.X, binaryExpr.Y),
+		}, ""
+	}
+
+	if left, right, ok := unsafeArithmeticComparison(pass, binaryExpr); ok && !ordered {
+		switch {
+		case !opts.enabled("unsafe"):
+			return nil, "rule unsafe is disabled"
+		case !opts.reportable(left, kinds):
+			return nil, excluded(left)
+		case !opts.reportable(right, kinds):
+			return nil, excluded(right)
+		}
+		return &analysis.Diagnostic{
+			Pos:      binaryExpr.Pos(),
+			End:      binaryExpr.End(),
+			Category: "unsafe",
+			Message:  fmt.Sprintf("comparing pointers to basic types computed with unsafe pointer arithmetic%s: %v and %v", with, left, right),
+			Related:  operandDeclarations(pass, binaryExpr.X, binaryExpr.Y),
+		}, ""
+	}
+
+	if !ordered && opts.enabled("boxed") {
+		if left, right, ok := boxedComparison(pass, binaryExpr, boxedVars()); ok {
+			switch {
+			case !opts.reportable(left, kinds):
+				return nil, excluded(left)
+			case !opts.reportable(right, kinds):
+				return nil, excluded(right)
+			}
+			return &analysis.Diagnostic{
+				Pos:      binaryExpr.Pos(),
+				End:      binaryExpr.End(),
+				Category: "boxed",
+				Message:  fmt.Sprintf("comparing interfaces holding pointers to basic types%s: %v and %v", with, left, right),
+				Related:  operandDeclarations(pass, binaryExpr.X, binaryExpr.Y),
+			}, ""
+		}
+	}
+
+	if !isPointerType(pass, binaryExpr.X) || !isPointerType(pass, binaryExpr.Y) {
+		return nil, "operands are not both pointers"
+	}
+	leftType := getUnderlyingType(pass, binaryExpr.X)
+	rightType := getUnderlyingType(pass, binaryExpr.Y)
+	switch {
+	case !opts.reportable(leftType, kinds):
+		return nil, excluded(leftType)
+	case !opts.reportable(rightType, kinds):
+		return nil, excluded(rightType)
+	}
+	// Ordered comparisons don't compile, so they have no category of their
+	// own and are only reported as such.
+	var class Category
+	if !ordered {
+		class, _ = ClassifyComparison(pass, binaryExpr)
+	}
+	category := "same-type"
+	switch {
+	case ordered:
+		category = "ordered"
+	case class == CategoryCrossType:
+		category = "cross-type"
+	case class == CategoryAddressOf:
+		category = "address-of"
+	case class == CategorySelf:
+		category = "self"
+	}
+	if !opts.enabled(category) {
+		return nil, "rule " + category + " is disabled"
+	}
+	message := fmt.Sprintf("comparing pointers to basic types%s: %v and %v", with, leftType, rightType)
+	if inAssertion(pass, stack) {
+		message = fmt.Sprintf("comparing pointers to basic types%s in a test assertion: %v and %v", with, leftType, rightType)
+	}
+	// Comparing the values instead doesn't fix comparing an operand, or its
+	// address, with itself, so there is nothing to suggest.
+	self := class == CategorySelf
+	variable, sameAddr := sameAddress(pass, binaryExpr.X, binaryExpr.Y)
+	// explained is set when the message already suggests the dereferenced
+	// comparison.
+	var explained bool
+	fixMode := opts.fixMode()
+	if ordered {
+		message = fmt.Sprintf("ordering pointers to basic types%s does not compile: %v and %v", with, leftType, rightType)
+	} else if self && sameAddr {
+		message = fmt.Sprintf("comparing addresses of the same variable%s: %s; always %v", with, variable, binaryExpr.Op == token.EQL)
+		fixMode = FixNone
+	} else if self {
+		message = fmt.Sprintf("comparing a pointer to itself%s; always %v", with, binaryExpr.Op == token.EQL)
+		fixMode = FixNone
+	} else if isNewCall(pass, binaryExpr.X) || isNewCall(pass, binaryExpr.Y) || separateAllocations(pass, binaryExpr, stack) {
+		message = fmt.Sprintf("comparing%s against a freshly allocated pointer to %v from new; always %v", with, leftType, binaryExpr.Op == token.NEQ)
+	} else if base, kind, ok := sameIndexedBase(pass, binaryExpr.X, binaryExpr.Y); ok {
+		message = fmt.Sprintf("comparing pointers to basic types%s from the same %s %s: %v and %v; did you mean %s?", with, kind, base, leftType, rightType, derefComparison(binaryExpr))
+		explained = true
+	}
+	if opts.enabled("if-hint") && selectsConstant(pass, stack) {
+		message += " (if/else branches differ only by a constant; did you mean to compare the values?)"
+	}
+	if !ordered && opts.enabled("loop-hint") && searchesRange(pass, binaryExpr, stack) {
+		message += " (searching a slice of pointers finds the same pointer, not an equal value; did you mean to compare the values?)"
+	}
+	if opts.ExplainFix && !self && !explained {
+		message += "; consider: " + derefComparison(binaryExpr)
+	}
+	return &analysis.Diagnostic{
+		Pos:            binaryExpr.Pos(),
+		End:            binaryExpr.End(),
+		Category:       category,
+		Message:        message,
+		Related:        operandDeclarations(pass, binaryExpr.X, binaryExpr.Y),
+		SuggestedFixes: suggestedFixes(pass, binaryExpr, stack, fixMode, opts.IdentityHelper),
+	}, ""
+}
+
+// excluded explains why pointers to t are not reported.
+func excluded(t types.Type) string {
+	if t == nil {
+		return "operand has no type"
+	}
+	if _, ok := basicTypes(t); !ok {
+		return fmt.Sprintf("%v is not a basic type", t)
+	}
+	return fmt.Sprintf("%v is excluded by -kinds or -ignore-types", t)
+}
+
+// trace logs the decision taken for expr to Options.Logger at debug level:
+// the diagnostic reported, or if d is nil the reason nothing was.
+func (c *checker) trace(pass *analysis.Pass, expr *ast.BinaryExpr, d *analysis.Diagnostic, reason string) {
+	logger := c.opts.Logger
+	if logger == nil || !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		"pos", pass.Fset.Position(expr.Pos()).String(),
+		"op", expr.Op.String(),
+		"left", types.TypeString(pass.TypesInfo.TypeOf(expr.X), nil),
+		"right", types.TypeString(pass.TypesInfo.TypeOf(expr.Y), nil),
+	}
+	if d != nil {
+		attrs = append(attrs, "decision", "reported", "rule", d.Category)
+	} else {
+		attrs = append(attrs, "decision", "skipped", "reason", reason)
+	}
+	logger.Debug("binary expression", attrs...)
+}
+
+// selectsConstant reports whether the comparison at the top of stack is the sole
+// condition of an if/else whose branches each consist of a single assignment to
+// the same target, or a single return, differing only by a constant value.
+func selectsConstant(pass *analysis.Pass, stack []ast.Node) bool {
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	ifStmt, ok := stack[i].(*ast.IfStmt)
+	if !ok || ifStmt.Init != nil || ast.Unparen(ifStmt.Cond) != stack[len(stack)-1] {
+		return false
+	}
+	elseBlock, ok := ifStmt.Else.(*ast.BlockStmt)
+	if !ok || len(ifStmt.Body.List) != 1 || len(elseBlock.List) != 1 {
+		return false
+	}
+
+	switch then := ifStmt.Body.List[0].(type) {
+	case *ast.AssignStmt:
+		other, ok := elseBlock.List[0].(*ast.AssignStmt)
+		if !ok || len(then.Lhs) != 1 || len(other.Lhs) != 1 || len(then.Rhs) != 1 || len(other.Rhs) != 1 {
+			return false
+		}
+		return types.ExprString(then.Lhs[0]) == types.ExprString(other.Lhs[0]) &&
+			isConstant(pass, then.Rhs[0]) && isConstant(pass, other.Rhs[0])
+	case *ast.ReturnStmt:
+		other, ok := elseBlock.List[0].(*ast.ReturnStmt)
+		if !ok || len(then.Results) != 1 || len(other.Results) != 1 {
+			return false
+		}
+		return isConstant(pass, then.Results[0]) && isConstant(pass, other.Results[0])
+	}
+	return false
+}
+
+// searchesRange reports whether expr compares an element of a slice or array
+// of pointers, from a range loop over it enclosing expr, with anything else,
+// e.g. p == target in for _, p := range ptrs. The element is either the
+// loop's value variable or the ranged expression indexed by its key.
+func searchesRange(pass *analysis.Pass, expr *ast.BinaryExpr, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.FuncLit); ok {
+			return false
+		}
+		loop, ok := stack[i].(*ast.RangeStmt)
+		if !ok || !rangesOverPointers(pass, loop.X) {
+			continue
+		}
+		x, y := rangeElement(pass, loop, expr.X), rangeElement(pass, loop, expr.Y)
+		if x != y {
+			return true
+		}
+	}
+	return false
+}
+
+// rangesOverPointers reports whether x is a slice or array of pointers, or a
+// pointer to such an array.
+func rangesOverPointers(pass *analysis.Pass, x ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(x)
+	if t == nil {
+		return false
+	}
+	under := t.Underlying()
+	if ptr, ok := under.(*types.Pointer); ok {
+		under = ptr.Elem().Underlying()
+	}
+	var elem types.Type
+	switch under := under.(type) {
+	case *types.Slice:
+		elem = under.Elem()
+	case *types.Array:
+		elem = under.Elem()
+	default:
+		return false
+	}
+	_, ok := types.Unalias(elem).(*types.Pointer)
+	return ok
+}
+
+// rangeElement reports whether operand is the element loop is at: its value
+// variable, or the ranged expression indexed by its key variable.
+func rangeElement(pass *analysis.Pass, loop *ast.RangeStmt, operand ast.Expr) bool {
+	operand = ast.Unparen(operand)
+	if ident, ok := operand.(*ast.Ident); ok && loop.Value != nil {
+		value, ok := loop.Value.(*ast.Ident)
+		return ok && value.Name != "_" && pass.TypesInfo.ObjectOf(ident) == pass.TypesInfo.ObjectOf(value)
+	}
+	index, ok := operand.(*ast.IndexExpr)
+	if !ok || loop.Key == nil || types.ExprString(index.X) != types.ExprString(loop.X) {
+		return false
+	}
+	key, ok := loop.Key.(*ast.Ident)
+	indexIdent, isIdent := ast.Unparen(index.Index).(*ast.Ident)
+	return ok && isIdent && key.Name != "_" && pass.TypesInfo.ObjectOf(indexIdent) == pass.TypesInfo.ObjectOf(key)
+}
+
+// inAssertion reports whether the comparison at the top of stack is passed
+// directly to an assertion helper, e.g. assert.True(t, p == q). Helpers are
+// functions and methods from packages named assert or require, like testify's,
+// and functions whose names start with assert, require or expect, like
+// hand-written test helpers.
+func inAssertion(pass *analysis.Pass, stack []ast.Node) bool {
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	call, ok := stack[i].(*ast.CallExpr)
+	if !ok || !slices.Contains(call.Args, stack[i+1].(ast.Expr)) {
+		return false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok {
+		return false
+	}
+	if pkg := fn.Pkg(); pkg != nil && (pkg.Name() == "assert" || pkg.Name() == "require") {
+		return true
+	}
+	name := strings.ToLower(fn.Name())
+	return strings.HasPrefix(name, "assert") || strings.HasPrefix(name, "require") || strings.HasPrefix(name, "expect")
+}
+
+func isConstant(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	return ok && tv.Value != nil
+}
+
+// operandDeclarations returns the declaration position of every operand that
+// is an identifier resolving to a variable, so editors can jump to it.
+func operandDeclarations(pass *analysis.Pass, operands ...ast.Expr) []analysis.RelatedInformation {
+	var related []analysis.RelatedInformation
+	seen := make(map[types.Object]bool)
+	for _, operand := range operands {
+		ident, ok := ast.Unparen(operand).(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok || !obj.Pos().IsValid() || seen[obj] {
+			continue
+		}
+		seen[obj] = true
+		related = append(related, analysis.RelatedInformation{
+			Pos:     obj.Pos(),
+			Message: fmt.Sprintf("%s declared here", ident.Name),
+		})
+	}
+	return related
+}
+
+// identityComparison reports whether expr compares the values behind two
+// pointers, *a == *b, to the same type listed in identityTypes, returning that
+// type.
+func identityComparison(pass *analysis.Pass, expr *ast.BinaryExpr, identityTypes []string) (types.Type, bool) {
+	if len(identityTypes) == 0 {
+		return nil, false
+	}
+	left, ok := ast.Unparen(expr.X).(*ast.StarExpr)
+	if !ok {
+		return nil, false
+	}
+	right, ok := ast.Unparen(expr.Y).(*ast.StarExpr)
+	if !ok || !isPointerType(pass, left.X) || !isPointerType(pass, right.X) {
+		return nil, false
+	}
+	elemType := getUnderlyingType(pass, left.X)
+	if !types.Identical(elemType, getUnderlyingType(pass, right.X)) || !slices.Contains(identityTypes, qualifiedName(elemType)) {
+		return nil, false
+	}
+	return elemType, true
+}
+
+// nilComparison reports whether expr compares a pointer against nil, returning
+// the pointer's element type. Nil converted to a pointer type, e.g.
+// (*int)(nil), counts as nil.
+func nilComparison(pass *analysis.Pass, expr *ast.BinaryExpr) (types.Type, bool) {
+	for _, pair := range [][2]ast.Expr{{expr.X, expr.Y}, {expr.Y, expr.X}} {
+		if isPointerType(pass, pair[0]) && isNil(pass, pair[1]) {
+			return getUnderlyingType(pass, pair[0]), true
+		}
+	}
+	return nil, false
+}
+
+// isNil reports whether expr is nil, or nil converted to another type such as
+// (*int)(nil) or unsafe.Pointer(nil).
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if pass.TypesInfo.Types[expr].IsNil() {
+		return true
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !pass.TypesInfo.Types[call.Fun].IsType() {
+		return false
+	}
+	return isNil(pass, call.Args[0])
+}
+
+// uintptrComparison reports whether both operands of expr have the form
+// uintptr(unsafe.Pointer(p)) with p a pointer, returning the element types.
+func uintptrComparison(pass *analysis.Pass, expr *ast.BinaryExpr) (types.Type, types.Type, bool) {
+	left, ok := uintptrOfPointer(pass, expr.X)
+	if !ok {
+		return nil, nil, false
+	}
+	right, ok := uintptrOfPointer(pass, expr.Y)
+	if !ok {
+		return nil, nil, false
+	}
+	return getUnderlyingType(pass, left), getUnderlyingType(pass, right), true
+}
+
+// unsafeArithmeticComparison reports whether expr compares two pointers at
+// least one of which is computed with unsafe pointer arithmetic, e.g.
+// (*int)(unsafe.Add(unsafe.Pointer(p), 8)), returning the element types.
+func unsafeArithmeticComparison(pass *analysis.Pass, expr *ast.BinaryExpr) (types.Type, types.Type, bool) {
+	if !isPointerType(pass, expr.X) || !isPointerType(pass, expr.Y) {
+		return nil, nil, false
+	}
+	if !unsafeArithmetic(pass, expr.X) && !unsafeArithmetic(pass, expr.Y) {
+		return nil, nil, false
+	}
+	return getUnderlyingType(pass, expr.X), getUnderlyingType(pass, expr.Y), true
+}
+
+// unsafeArithmetic reports whether expr is a chain of conversions ending in a
+// call to unsafe.Add or in uintptr arithmetic, such as
+// unsafe.Pointer(uintptr(unsafe.Pointer(p)) + 8).
+func unsafeArithmetic(pass *analysis.Pass, expr ast.Expr) bool {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.CallExpr:
+			if builtin, ok := typeutil.Callee(pass.TypesInfo, e).(*types.Builtin); ok {
+				return builtin.Name() == "Add"
+			}
+			if len(e.Args) != 1 || !pass.TypesInfo.Types[e.Fun].IsType() {
+				return false
+			}
+			expr = e.Args[0]
+		case *ast.BinaryExpr:
+			if e.Op != token.ADD && e.Op != token.SUB {
+				return false
+			}
+			t, ok := types.Unalias(pass.TypesInfo.TypeOf(e)).(*types.Basic)
+			return ok && t.Kind() == types.Uintptr
+		default:
+			return false
+		}
+	}
+}
+
+// uintptrOfPointer returns p if expr is uintptr(unsafe.Pointer(p)) and p is a
+// pointer.
+func uintptrOfPointer(pass *analysis.Pass, expr ast.Expr) (ast.Expr, bool) {
+	inner, ok := conversionTo(pass, expr, types.Typ[types.Uintptr])
+	if !ok {
+		return nil, false
+	}
+	p, ok := conversionTo(pass, inner, types.Typ[types.UnsafePointer])
+	if !ok || !isPointerType(pass, p) {
+		return nil, false
+	}
+	return p, true
+}
+
+// conversionTo returns the operand of expr if expr is a conversion to t.
+func conversionTo(pass *analysis.Pass, expr ast.Expr, t types.Type) (ast.Expr, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, false
+	}
+	fun := pass.TypesInfo.Types[call.Fun]
+	if !fun.IsType() || !types.Identical(fun.Type, t) {
+		return nil, false
+	}
+	return call.Args[0], true
+}
+
+// sameOperand reports whether x and y denote the same variable, either the
+// same identifier or the same field selected from the same variable, e.g. a.x
+// and a.x, also when re-addressed as in &*a.x. Anything that could evaluate
+// differently each time, like calls and index expressions, never counts as
+// the same.
+func sameOperand(pass *analysis.Pass, x, y ast.Expr) bool {
+	x, y = readdressed(x), readdressed(y)
+	switch x := x.(type) {
+	case *ast.Ident:
+		y, ok := y.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		obj := pass.TypesInfo.ObjectOf(x)
+		_, isVar := obj.(*types.Var)
+		return isVar && obj == pass.TypesInfo.ObjectOf(y)
+	case *ast.SelectorExpr:
+		y, ok := y.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		obj := pass.TypesInfo.ObjectOf(x.Sel)
+		if _, isVar := obj.(*types.Var); !isVar || obj != pass.TypesInfo.ObjectOf(y.Sel) {
+			return false
+		}
+		// A package-qualified variable has no receiver to compare.
+		if pass.TypesInfo.Selections[x] == nil {
+			return pass.TypesInfo.Selections[y] == nil
+		}
+		return sameOperand(pass, x.X, y.X)
+	}
+	return false
+}
+
+// sameAddress reports whether x and y both take the address of the same
+// variable, e.g. &a.N and &a.N, returning the source of the variable.
+func sameAddress(pass *analysis.Pass, x, y ast.Expr) (string, bool) {
+	x, y = readdressed(x), readdressed(y)
+	if !isAddressOf(x) || !isAddressOf(y) {
+		return "", false
+	}
+	variable := x.(*ast.UnaryExpr).X
+	if !sameOperand(pass, variable, y.(*ast.UnaryExpr).X) {
+		return "", false
+	}
+	return types.ExprString(readdressed(variable)), true
+}
+
+// isSameAddress reports whether x and y both take the address of the same
+// variable.
+func isSameAddress(pass *analysis.Pass, x, y ast.Expr) bool {
+	_, ok := sameAddress(pass, x, y)
+	return ok
+}
+
+// readdressed strips parentheses and pairs of & and * that cancel out from
+// expr, returning p for &*p or &(*(&*p)).
+func readdressed(expr ast.Expr) ast.Expr {
+	for {
+		expr = ast.Unparen(expr)
+		addr, ok := expr.(*ast.UnaryExpr)
+		if !ok || addr.Op != token.AND {
+			return expr
+		}
+		star, ok := ast.Unparen(addr.X).(*ast.StarExpr)
+		if !ok {
+			return expr
+		}
+		expr = star.X
+	}
+}
+
+// sameIndexedBase reports whether x and y are index expressions on the same
+// slice, array or map with indices computed at runtime, e.g. s[i] and s[j],
+// returning the source of the indexed expression and its kind. Such
+// comparisons usually check whether two slots hold the same pointer where the
+// values were meant.
+func sameIndexedBase(pass *analysis.Pass, x, y ast.Expr) (string, string, bool) {
+	left, ok := ast.Unparen(x).(*ast.IndexExpr)
+	if !ok {
+		return "", "", false
+	}
+	right, ok := ast.Unparen(y).(*ast.IndexExpr)
+	if !ok {
+		return "", "", false
+	}
+	base := types.ExprString(left.X)
+	if base != types.ExprString(right.X) || (isConstant(pass, left.Index) && isConstant(pass, right.Index)) {
+		return "", "", false
+	}
+	t := pass.TypesInfo.TypeOf(left.X)
+	if t == nil {
+		return "", "", false
+	}
+	under := t.Underlying()
+	if ptr, ok := under.(*types.Pointer); ok {
+		under = ptr.Elem().Underlying()
+	}
+	switch under.(type) {
+	case *types.Slice:
+		return base, "slice", true
+	case *types.Array:
+		return base, "array", true
+	case *types.Map:
+		return base, "map", true
+	}
+	return "", "", false
+}
+
+// isNewCall reports whether expr is a call to the builtin new.
+func isNewCall(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	builtin, ok := pass.TypesInfo.Uses[ident].(*types.Builtin)
+	return ok && builtin.Name() == "new"
+}
+
+// separateAllocations reports whether both operands of expr are distinct
+// local variables that are only ever assigned a call to new, where they are
+// declared, e.g. a and b after a, b := new(T), new(T). Like comparing against
+// new directly, they can never be equal.
+func separateAllocations(pass *analysis.Pass, expr *ast.BinaryExpr, stack []ast.Node) bool {
+	x, y := localVar(pass, expr.X), localVar(pass, expr.Y)
+	if x == nil || y == nil || x == y {
+		return false
+	}
+	var body *ast.BlockStmt
+	for _, n := range stack {
+		if decl, ok := n.(*ast.FuncDecl); ok {
+			body = decl.Body
+			break
+		}
+		if lit, ok := n.(*ast.FuncLit); ok {
+			body = lit.Body
+			break
+		}
+	}
+	return body != nil && onlyAllocated(pass, body, x) && onlyAllocated(pass, body, y)
+}
+
+// localVar returns the variable expr names, if it is declared in a function.
+func localVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || v.IsField() || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
+
+// onlyAllocated reports whether v, declared in body, is initialized with a
+// call to new and never assigned again nor has its address taken.
+func onlyAllocated(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var) bool {
+	is := func(ident ast.Expr) bool {
+		id, ok := ast.Unparen(ident).(*ast.Ident)
+		return ok && (pass.TypesInfo.Defs[id] == v || pass.TypesInfo.Uses[id] == v)
+	}
+	allocated, reassigned := false, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if !is(lhs) {
+					continue
+				}
+				id := ast.Unparen(lhs).(*ast.Ident)
+				if pass.TypesInfo.Defs[id] == v && len(n.Lhs) == len(n.Rhs) && isNewCall(pass, n.Rhs[i]) {
+					allocated = true
+				} else {
+					reassigned = true
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if !is(name) {
+					continue
+				}
+				if len(n.Names) == len(n.Values) && isNewCall(pass, n.Values[i]) {
+					allocated = true
+				} else {
+					reassigned = true
+				}
+			}
+		case *ast.RangeStmt:
+			if (n.Key != nil && is(n.Key)) || (n.Value != nil && is(n.Value)) {
+				reassigned = true
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND && is(n.X) {
+				reassigned = true
+			}
+		}
+		return !reassigned
+	})
+	return allocated && !reassigned
+}
+
+// isPointerType reports whether expr has a pointer type. Operands are
+// classified by the type the type checker records rather than by their shape,
+// so unusual expressions need no special handling: &arr[i] of an array is an
+// ordinary pointer, while &m[k] doesn't compile because map elements aren't
+// addressable, and in code with type errors has an invalid type rather than a
+// pointer type.
+func isPointerType(pass *analysis.Pass, expr ast.Expr) bool {
+	exprType := pass.TypesInfo.TypeOf(expr)
+	if exprType == nil {
+		return false
+	}
+
+	_, isPtr := types.Unalias(exprType).(*types.Pointer)
+	return isPtr
+}
+
+// getUnderlyingType returns the element type of the pointer expr, or the type
+// of expr if it isn't a pointer. It goes by the type of the whole expression,
+// so a conversion to a pointer type, (*int)(p), yields its target's element
+// type, and a conversion of a dereferenced value, int(*p), yields int itself.
+func getUnderlyingType(pass *analysis.Pass, expr ast.Expr) types.Type {
+	exprType := pass.TypesInfo.TypeOf(expr)
+	if exprType == nil {
+		return nil
+	}
+
+	if ptr, ok := types.Unalias(exprType).(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+
+	return exprType
+}
+
+func isBasicType(t types.Type) bool {
+	_, isBasic := basicTypes(t)
+	return isBasic
+}
+
+// basicTypes returns the basic types t may stand for: its underlying type, or
+// for a type parameter every term of its constraint. It reports false if t
+// may be something other than a basic type, e.g. a type parameter constrained
+// by any or comparable.
+func basicTypes(t types.Type) ([]*types.Basic, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if tp, ok := t.(*types.TypeParam); ok {
+		t = tp.Constraint()
+	}
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		return []*types.Basic{underlying}, true
+	case *types.Interface:
+		return constraintBasicTypes(underlying)
+	}
+	return nil, false
+}
+
+// constraintBasicTypes returns the basic types in the type set of iface, which
+// must be restricted by at least one union or type term made only of basic
+// types.
+func constraintBasicTypes(iface *types.Interface) ([]*types.Basic, bool) {
+	var basics []*types.Basic
+	restricted := false
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		embedded := iface.EmbeddedType(i)
+		if nested, ok := embedded.Underlying().(*types.Interface); ok {
+			// The type set is the intersection of the embedded elements, so
+			// interfaces that don't restrict it to basic types are skipped.
+			if nestedBasics, ok := constraintBasicTypes(nested); ok {
+				basics = append(basics, nestedBasics...)
+				restricted = true
+			}
+			continue
+		}
+		terms := []types.Type{embedded}
+		if union, ok := embedded.(*types.Union); ok {
+			terms = terms[:0]
+			for j := 0; j < union.Len(); j++ {
+				terms = append(terms, union.Term(j).Type())
+			}
+		}
+		for _, term := range terms {
+			termBasics, ok := basicTypes(term)
+			if !ok {
+				return nil, false
+			}
+			basics = append(basics, termBasics...)
+		}
+		restricted = true
+	}
+	return basics, restricted
+}
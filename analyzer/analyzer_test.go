@@ -0,0 +1,253 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"fmt"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	analysischecker "golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/packages"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func testdata(t *testing.T) string {
+	dir, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzer(),
+		"addressof", "aliases", "arrays", "builtins", "channels", "closures", "commaok", "conversions", "deferred", "diagcalls", "directives", "doubleptr", "duplicates", "enums", "functions", "generated", "genericmethods", "generics", "goroutines", "ifaces", "initstmts", "kinds", "multipkg/...", "namedtypes", "newcalls", "overlay", "readdress", "reflection", "samebase", "sameline", "selfcompare", "tuples", "typeswitch", "related", "unicodenames", "variadic")
+}
+
+func TestAnalyzerAcrossPackages(t *testing.T) {
+	analysistest.Run(t, filepath.Join(testdata(t), "crosspkg"), NewPtrAnalyzer(), "crosspkg/...")
+}
+
+// TestStandardDriver runs the analyzer next to another one through the
+// checker package that backs multichecker, rather than analysistest or the
+// command's own loading, to make sure it doesn't depend on either.
+func TestStandardDriver(t *testing.T) {
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: filepath.Join(testdata(t), "crosspkg")}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ptrAnalyzer := NewPtrAnalyzer()
+	if err := analysis.Validate([]*analysis.Analyzer{printf.Analyzer, ptrAnalyzer}); err != nil {
+		t.Fatal(err)
+	}
+	graph, err := analysischecker.Analyze([]*analysis.Analyzer{printf.Analyzer, ptrAnalyzer}, pkgs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for act := range graph.All() {
+		if act.Err != nil {
+			t.Errorf("%v: %v", act, act.Err)
+		}
+		if act.IsRoot && act.Analyzer == ptrAnalyzer {
+			for _, d := range act.Diagnostics {
+				messages = append(messages, fmt.Sprintf("%s: %s", filepath.Base(act.Package.Fset.Position(d.Pos).String()), d.Message))
+			}
+		}
+	}
+	slices.Sort(messages)
+	want := []string{
+		"caller.go:27:6: comparing pointers to basic types: int and int",
+		"caller.go:28:6: comparing pointers to basic types: int and int",
+		"caller.go:29:6: comparing pointers to basic types: crosspkg/names.Name and crosspkg/names.Name",
+	}
+	if !slices.Equal(messages, want) {
+		t.Errorf("got findings %q, want %q", messages, want)
+	}
+}
+
+func TestAnalyzerAssertions(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{CheckTests: true}), "assertions")
+}
+
+func TestAnalyzerIfHint(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{IfHint: true}), "ifhint")
+}
+
+func TestAnalyzerExportedOnly(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{ExportedOnly: true}), "exportedonly")
+}
+
+func TestAnalyzerLoopHint(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{LoopHint: true}), "loophint")
+}
+
+func TestAnalyzerExplainFix(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{ExplainFix: true}), "explainfix")
+}
+
+func TestAnalyzerCheckBoxed(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{CheckBoxed: true}), "boxed")
+}
+
+func TestAnalyzerIdentity(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{
+		FlagValueCompareOfIdentityTypes: true,
+		IdentityTypes:                   []string{"identity.Entry", "identity.Token"},
+	}), "identity")
+}
+
+func TestAnalyzerIgnoreTypesAcrossImportNames(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{IgnoreTypes: []string{"importalias/units.Meters", "time.Duration"}}), "importalias")
+}
+
+func TestAnalyzerTypedNil(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{FlagNil: true}), "typednil")
+}
+
+func TestAnalyzerFlagNilIn(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{FlagNilIn: []string{"New*", "Cache.Get", "must"}}), "nilscope")
+
+	if err := (&Options{FlagNilIn: []string{"New["}}).validateNilScopes(); err == nil {
+		t.Error("expected an error for a malformed -flag-nil-in pattern")
+	}
+}
+
+func TestAnalyzerReportOperator(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{ReportOperator: true, FlagNil: true, CheckUnsafe: true, IncludeOrdered: true, CheckBoxed: true}), "operators")
+}
+
+func TestAnalyzerCheckUnsafe(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{CheckUnsafe: true}), "uintptrs")
+}
+
+// TestMessageFormat checks that every kind of finding follows the go vet
+// conventions for diagnostics, so they don't stand out next to the standard
+// checks under singlechecker: lowercase, a single line, no trailing period.
+// Ordered comparisons share the same messages but only occur in code that
+// doesn't type-check, so they can't be covered here.
+func TestMessageFormat(t *testing.T) {
+	everyRule := make(map[string]bool, len(Rules))
+	for _, r := range Rules {
+		everyRule[r.ID] = true
+	}
+	results := analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{Rules: everyRule}), "messages")
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			first, _ := utf8.DecodeRuneInString(d.Message)
+			if !unicode.IsLower(first) || strings.HasSuffix(d.Message, ".") || strings.ContainsAny(d.Message, "\n\t") {
+				t.Errorf("%v: message %q doesn't follow go vet conventions", result.Pass.Fset.Position(d.Pos), d.Message)
+			}
+			for _, r := range d.Related {
+				if strings.HasSuffix(r.Message, ".") || strings.ContainsAny(r.Message, "\n\t") {
+					t.Errorf("%v: related message %q doesn't follow go vet conventions", result.Pass.Fset.Position(r.Pos), r.Message)
+				}
+			}
+		}
+	}
+}
+
+func TestSelfRule(t *testing.T) {
+	results := analysistest.Run(t, testdata(t), NewPtrAnalyzer(), "selfcompare")
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			if self := strings.Contains(d.Message, "itself"); self != (d.Category == "self") {
+				t.Errorf("%v: %q reported by rule %s", result.Pass.Fset.Position(d.Pos), d.Message, d.Category)
+			}
+		}
+	}
+}
+
+func TestAnalyzerCheckUnsafeOrdered(t *testing.T) {
+	analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{CheckUnsafe: true, IncludeOrdered: true}), "uintptrorder")
+}
+
+func TestAnalyzersDoNotShareOptions(t *testing.T) {
+	hinting := NewPtrAnalyzer()
+	if err := hinting.Flags.Set("if-hint", "true"); err != nil {
+		t.Fatal(err)
+	}
+	plain := NewPtrAnalyzer()
+
+	t.Run("hinting", func(t *testing.T) {
+		t.Parallel()
+		analysistest.Run(t, testdata(t), hinting, "ifhint")
+	})
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+		analysistest.Run(t, testdata(t), plain, "related", "variadic")
+	})
+	t.Run("unsafe", func(t *testing.T) {
+		t.Parallel()
+		analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{CheckUnsafe: true}), "uintptrs")
+	})
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, testdata(t), NewPtrAnalyzer(), "fixes")
+	analysistest.RunWithSuggestedFixes(t, testdata(t), NewPtrAnalyzerWithOptions(Options{FixMode: FixComment}), "fixcomment")
+	analysistest.RunWithSuggestedFixes(t, testdata(t), NewPtrAnalyzerWithOptions(Options{IdentityHelper: "ptrutil.SamePtr"}), "identityhelper")
+
+	for _, helper := range []string{"SamePtr", "ptrutil.", "ptrutil.Same-Ptr"} {
+		if err := validateIdentityHelper(helper); err == nil {
+			t.Errorf("expected an error for -identity-helper %q", helper)
+		}
+	}
+}
+
+func TestFixModeNone(t *testing.T) {
+	results := analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{FixMode: FixNone}), "fixes")
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			if len(d.SuggestedFixes) != 0 {
+				t.Errorf("unexpected suggested fix at %v", result.Pass.Fset.Position(d.Pos))
+			}
+		}
+	}
+}
+
+func TestNoSuggestions(t *testing.T) {
+	results := analysistest.Run(t, testdata(t), NewPtrAnalyzerWithOptions(Options{FixMode: FixComment, NoSuggestions: true}), "fixes")
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			if len(d.SuggestedFixes) != 0 {
+				t.Errorf("unexpected suggested fix at %v", result.Pass.Fset.Position(d.Pos))
+			}
+		}
+	}
+}
+
+func TestFixModeValidation(t *testing.T) {
+	ptrAnalyzer := NewPtrAnalyzer()
+	ptrAnalyzer.Flags.SetOutput(io.Discard)
+	if err := ptrAnalyzer.Flags.Parse([]string{"-fix-mode=deref-left"}); err == nil {
+		t.Error("expected an invalid -fix-mode to be rejected")
+	}
+	if err := ptrAnalyzer.Flags.Parse([]string{"-fix-mode=comment"}); err != nil {
+		t.Error(err)
+	}
+}
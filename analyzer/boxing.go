@@ -0,0 +1,143 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+)
+
+// boxedVars maps the local interface variables of a file to the element type
+// of the pointer to a basic type they are initialized with. Variables that
+// are assigned again or have their address taken are left out. There is no
+// data flow analysis: a variable is only known to hold a pointer if its
+// initializer is the only value it is ever given.
+func boxedVars(pass *analysis.Pass, file *ast.File) map[*types.Var]types.Type {
+	elems := make(map[*types.Var]types.Type)
+	unknown := make(map[*types.Var]bool)
+	record := func(ident *ast.Ident, value ast.Expr) {
+		obj, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok || !isInterfaceValue(obj.Type()) {
+			return
+		}
+		// Package level variables can be assigned in other files.
+		if value == nil || obj.Parent() == obj.Pkg().Scope() {
+			unknown[obj] = true
+			return
+		}
+		elem := boxedElem(pass, value, nil)
+		if elem == nil {
+			unknown[obj] = true
+			return
+		}
+		elems[obj] = elem
+	}
+	reassign := func(expr ast.Expr) {
+		if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+			if obj, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var); ok {
+				unknown[obj] = true
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				var value ast.Expr
+				if len(n.Values) == len(n.Names) {
+					value = n.Values[i]
+				}
+				record(name, value)
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if n.Tok != token.DEFINE || !ok || pass.TypesInfo.Defs[ident] == nil || len(n.Lhs) != len(n.Rhs) {
+					reassign(lhs)
+					continue
+				}
+				record(ident, n.Rhs[i])
+			}
+		case *ast.IncDecStmt:
+			reassign(n.X)
+		case *ast.RangeStmt:
+			reassign(n.Key)
+			reassign(n.Value)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				reassign(n.X)
+			}
+		}
+		return true
+	})
+
+	for obj := range unknown {
+		delete(elems, obj)
+	}
+	return elems
+}
+
+// boxedComparison reports whether expr compares two interfaces, or an
+// interface and a pointer, that are both known to hold pointers to basic
+// types, returning the element types. An operand is known to hold one if it
+// is a pointer itself, an explicit conversion of a pointer such as any(p), or
+// a variable from vars.
+func boxedComparison(pass *analysis.Pass, expr *ast.BinaryExpr, vars map[*types.Var]types.Type) (types.Type, types.Type, bool) {
+	if !isInterfaceValue(pass.TypesInfo.TypeOf(expr.X)) && !isInterfaceValue(pass.TypesInfo.TypeOf(expr.Y)) {
+		return nil, nil, false
+	}
+	left, right := boxedElem(pass, expr.X, vars), boxedElem(pass, expr.Y, vars)
+	if left == nil || right == nil {
+		return nil, nil, false
+	}
+	return left, right, true
+}
+
+func boxedElem(pass *analysis.Pass, expr ast.Expr, vars map[*types.Var]types.Type) types.Type {
+	expr = ast.Unparen(expr)
+	if isPointerType(pass, expr) {
+		return getUnderlyingType(pass, expr)
+	}
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if obj, ok := pass.TypesInfo.ObjectOf(expr).(*types.Var); ok {
+			return vars[obj]
+		}
+	case *ast.CallExpr:
+		if len(expr.Args) == 1 && pass.TypesInfo.Types[expr.Fun].IsType() && isPointerType(pass, expr.Args[0]) {
+			return getUnderlyingType(pass, expr.Args[0])
+		}
+	}
+	return nil
+}
+
+// isInterfaceValue reports whether t is an interface type, excluding type
+// parameters whose underlying type is their constraint.
+func isInterfaceValue(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if _, ok := types.Unalias(t).(*types.TypeParam); ok {
+		return false
+	}
+	return types.IsInterface(t)
+}
@@ -0,0 +1,87 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Category classifies an equality comparison involving a pointer to a basic
+// type, as returned by ClassifyComparison.
+type Category string
+
+const (
+	// CategorySameType compares two pointers to the same basic type.
+	CategorySameType Category = "same-type"
+	// CategoryCrossType compares pointers to different basic types, e.g. in
+	// generic code or code that doesn't type-check.
+	CategoryCrossType Category = "cross-type"
+	// CategoryAddressOf compares pointers to the same basic type where at
+	// least one operand takes an address with &, e.g. p == &x.
+	CategoryAddressOf Category = "address-of"
+	// CategoryNil compares a pointer to a basic type with nil.
+	CategoryNil Category = "nil"
+	// CategorySelf compares a pointer to itself, e.g. p == p, or the
+	// address of a variable to itself, e.g. &x == &x.
+	CategorySelf Category = "self"
+)
+
+// ClassifyComparison reports whether expr is an == or != comparison of
+// pointers to basic types, or of one with nil, and which category it falls
+// in. It only looks at types, so unlike the analyzer it ignores Options and
+// suppression directives.
+func ClassifyComparison(pass *analysis.Pass, expr *ast.BinaryExpr) (Category, bool) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return "", false
+	}
+	if elemType, ok := nilComparison(pass, expr); ok {
+		if !isBasicType(elemType) {
+			return "", false
+		}
+		return CategoryNil, true
+	}
+	if !isPointerType(pass, expr.X) || !isPointerType(pass, expr.Y) {
+		return "", false
+	}
+	leftType := getUnderlyingType(pass, expr.X)
+	rightType := getUnderlyingType(pass, expr.Y)
+	if !isBasicType(leftType) || !isBasicType(rightType) {
+		return "", false
+	}
+	switch {
+	case sameOperand(pass, expr.X, expr.Y):
+		return CategorySelf, true
+	case isSameAddress(pass, expr.X, expr.Y):
+		return CategorySelf, true
+	case !types.Identical(leftType, rightType):
+		return CategoryCrossType, true
+	case isAddressOf(expr.X) || isAddressOf(expr.Y):
+		return CategoryAddressOf, true
+	}
+	return CategorySameType, true
+}
+
+// isAddressOf reports whether expr takes an address with &.
+func isAddressOf(expr ast.Expr) bool {
+	unary, ok := ast.Unparen(expr).(*ast.UnaryExpr)
+	return ok && unary.Op == token.AND
+}
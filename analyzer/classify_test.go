@@ -0,0 +1,116 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"testing"
+)
+
+func TestClassifyComparison(t *testing.T) {
+	const src = `package p
+
+type celsius float64
+
+type pair struct{ a, b *int }
+
+func f[T int | string, U float64](p, q *int, s *string, c *celsius, x int, v, w *T, u *U, t pair, ip, iq **int, a, b any, ch chan *int) {
+	_ = p == q
+	_ = p != &x
+	_ = &x == &x
+	_ = p == nil
+	_ = nil != s
+	_ = p == p
+	_ = t.a == t.a
+	_ = t.a == t.b
+	_ = <-ch == <-ch
+	_ = c == c
+	_ = v == w
+	_ = *p == *q
+	_ = ip == iq
+	_ = ip == nil
+	_ = a == b
+	_ = x < x
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+		// Selections are needed to tell fields of different variables apart.
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("p", fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pass := &analysis.Pass{Fset: fset, Files: []*ast.File{file}, Pkg: pkg, TypesInfo: info}
+
+	want := []struct {
+		category Category
+		ok       bool
+	}{
+		{CategorySameType, true},
+		{CategoryAddressOf, true},
+		{CategorySelf, true},
+		{CategoryNil, true},
+		{CategoryNil, true},
+		{CategorySelf, true},
+		{CategorySelf, true},
+		{CategorySameType, true},
+		{CategorySameType, true},
+		{CategorySelf, true},
+		{CategorySameType, true},
+		{"", false},
+		{"", false},
+		{"", false},
+		{"", false},
+		{"", false},
+	}
+	var i int
+	ast.Inspect(file, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		expr := assign.Rhs[0].(*ast.BinaryExpr)
+		category, ok := ClassifyComparison(pass, expr)
+		if i >= len(want) {
+			t.Fatalf("more comparisons than expected results")
+		}
+		if category != want[i].category || ok != want[i].ok {
+			t.Errorf("ClassifyComparison(%s) = %q, %v; want %q, %v", types.ExprString(expr), category, ok, want[i].category, want[i].ok)
+		}
+		i++
+		return true
+	})
+	if i != len(want) {
+		t.Errorf("checked %d comparisons, want %d", i, len(want))
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Directives turning reporting off and back on for the lines between them.
+const (
+	disableDirective = "//ptrcmp:disable"
+	enableDirective  = "//ptrcmp:enable"
+)
+
+// span is a half-open range of positions in a file.
+type span struct{ start, end token.Pos }
+
+// disabledSpans returns the parts of file between a //ptrcmp:disable comment
+// and the next //ptrcmp:enable, or the end of the file if there is none.
+// Directives don't nest: a disable inside a disabled span and an enable
+// outside one are ignored.
+func disabledSpans(file *ast.File) []span {
+	var spans []span
+	disabled := false
+	var start token.Pos
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			switch {
+			case isDirective(c.Text, disableDirective) && !disabled:
+				disabled, start = true, c.Pos()
+			case isDirective(c.Text, enableDirective) && disabled:
+				disabled = false
+				spans = append(spans, span{start, c.End()})
+			}
+		}
+	}
+	if disabled {
+		spans = append(spans, span{start, file.FileEnd})
+	}
+	return spans
+}
+
+// isDirective reports whether the comment text is directive, optionally
+// followed by an explanation after a space.
+func isDirective(text, directive string) bool {
+	rest, ok := strings.CutPrefix(text, directive)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+func inSpans(spans []span, pos token.Pos) bool {
+	for _, s := range spans {
+		if s.start <= pos && pos < s.end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Modes for Options.FixMode, controlling the suggested fix attached to
+// pointer comparisons.
+const (
+	// FixDerefBoth dereferences both operands, e.g. *a == *b.
+	FixDerefBoth = "deref-both"
+	// FixNone attaches no suggested fix.
+	FixNone = "none"
+	// FixComment leaves the comparison alone and adds a TODO comment asking
+	// whether the values were meant to be compared.
+	FixComment = "comment"
+)
+
+var fixModes = []string{FixDerefBoth, FixNone, FixComment}
+
+func validateFixMode(mode string) error {
+	if mode != "" && !slices.Contains(fixModes, mode) {
+		return fmt.Errorf("invalid fix mode %q: must be one of %s", mode, strings.Join(fixModes, ", "))
+	}
+	return nil
+}
+
+// suggestedFixes returns the fixes for the pointer comparison expr under mode,
+// or calling helper instead if it is set and expr is an equality comparison.
+func suggestedFixes(pass *analysis.Pass, expr *ast.BinaryExpr, stack []ast.Node, mode, helper string) []analysis.SuggestedFix {
+	if mode != FixNone && helper != "" && (expr.Op == token.EQL || expr.Op == token.NEQ) {
+		fix, ok := identityHelperFix(pass, expr, helper)
+		if !ok {
+			return nil
+		}
+		return []analysis.SuggestedFix{fix}
+	}
+	switch mode {
+	case FixNone:
+		return nil
+	case FixComment:
+		pos := commentPos(pass, expr, stack)
+		return []analysis.SuggestedFix{{
+			Message: "Add a TODO asking whether the values were meant to be compared",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     pos,
+				End:     pos,
+				NewText: []byte(fmt.Sprintf("%s// TODO: did you mean %s?\n", lineIndent(pass, pos), derefComparison(expr))),
+			}},
+		}}
+	default:
+		return []analysis.SuggestedFix{{
+			Message:   "Compare the values instead of the pointers",
+			TextEdits: append(derefEdits(expr.X), derefEdits(expr.Y)...),
+		}}
+	}
+}
+
+// commentPos returns the start of the line holding the innermost statement
+// enclosing expr, where a -fix-mode=comment TODO goes.
+func commentPos(pass *analysis.Pass, expr *ast.BinaryExpr, stack []ast.Node) token.Pos {
+	pos := expr.Pos()
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stmt, ok := stack[i].(ast.Stmt); ok {
+			pos = stmt.Pos()
+			break
+		}
+	}
+	file := pass.Fset.File(pos)
+	return file.LineStart(file.Line(pos))
+}
+
+// lineIndent returns the leading whitespace of the line starting at pos, or
+// nothing if the source can't be read.
+func lineIndent(pass *analysis.Pass, pos token.Pos) string {
+	if pass.ReadFile == nil {
+		return ""
+	}
+	file := pass.Fset.File(pos)
+	src, err := pass.ReadFile(file.Name())
+	if err != nil || file.Size() != len(src) {
+		return ""
+	}
+	line := src[file.Offset(pos):]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// identityHelperFix rewrites the comparison expr to a call of helper with
+// both operands, negated for !=, importing helper's package into the file if
+// it isn't already. It fails if the package's name is shadowed where expr is.
+func identityHelperFix(pass *analysis.Pass, expr *ast.BinaryExpr, helper string) (analysis.SuggestedFix, bool) {
+	pkgPath, name := splitHelper(helper)
+	var file *ast.File
+	for _, f := range pass.Files {
+		if f.FileStart <= expr.Pos() && expr.Pos() < f.FileEnd {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	var edits []analysis.TextEdit
+	call := name
+	if pkgPath != pass.Pkg.Path() {
+		local, imported := importName(pass, file, pkgPath)
+		if !imported {
+			if !token.IsIdentifier(local) {
+				return analysis.SuggestedFix{}, false
+			}
+			edits = append(edits, importEdit(file, pkgPath))
+		}
+		if local != "." {
+			if obj := lookup(pass, expr.Pos(), local); obj != nil {
+				if _, isPkg := obj.(*types.PkgName); !isPkg {
+					return analysis.SuggestedFix{}, false
+				}
+			}
+			call = local + "." + name
+		}
+	}
+	if expr.Op == token.NEQ {
+		call = "!" + call
+	}
+	edits = append(edits,
+		analysis.TextEdit{Pos: expr.Pos(), End: expr.X.Pos(), NewText: []byte(call + "(")},
+		analysis.TextEdit{Pos: expr.X.End(), End: expr.Y.Pos(), NewText: []byte(", ")},
+		analysis.TextEdit{Pos: expr.Y.End(), End: expr.End(), NewText: []byte(")")},
+	)
+	return analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Call %s to mark the comparison of the pointers as intentional", helper),
+		TextEdits: edits,
+	}, true
+}
+
+// splitHelper splits a function qualified by import path, e.g.
+// "example.com/ptrutil.SamePtr", into the path and the function's name.
+func splitHelper(helper string) (pkgPath, name string) {
+	i := strings.LastIndex(helper, ".")
+	if i < 0 {
+		return "", helper
+	}
+	return helper[:i], helper[i+1:]
+}
+
+// validateIdentityHelper checks Options.IdentityHelper.
+func validateIdentityHelper(helper string) error {
+	if helper == "" {
+		return nil
+	}
+	if pkgPath, name := splitHelper(helper); pkgPath == "" || !token.IsIdentifier(name) {
+		return fmt.Errorf("invalid -identity-helper %q: must be a function qualified by import path, e.g. example.com/ptrutil.SamePtr", helper)
+	}
+	return nil
+}
+
+// importName returns the name pkgPath is imported as in file and true, or
+// the name it would be imported as and false if file doesn't import it other
+// than for side effects.
+func importName(pass *analysis.Pass, file *ast.File, pkgPath string) (string, bool) {
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err != nil || p != pkgPath {
+			continue
+		}
+		if spec.Name != nil && spec.Name.Name == "_" {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+		if name := pass.TypesInfo.PkgNameOf(spec); name != nil {
+			return name.Imported().Name(), true
+		}
+	}
+	for _, pkg := range pass.Pkg.Imports() {
+		if pkg.Path() == pkgPath {
+			return pkg.Name(), false
+		}
+	}
+	return path.Base(pkgPath), false
+}
+
+// importEdit adds an import of pkgPath to file: to its first import
+// declaration, adding parentheses around a single import, or as a declaration
+// of its own after the package clause if file imports nothing.
+func importEdit(file *ast.File, pkgPath string) analysis.TextEdit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			break
+		}
+		if gen.Lparen.IsValid() {
+			pos := gen.Lparen + 1
+			return analysis.TextEdit{Pos: pos, End: pos, NewText: []byte("\n\t" + strconv.Quote(pkgPath))}
+		}
+		spec := gen.Specs[0].(*ast.ImportSpec)
+		existing := spec.Path.Value
+		if spec.Name != nil {
+			existing = spec.Name.Name + " " + existing
+		}
+		return analysis.TextEdit{
+			Pos:     spec.Pos(),
+			End:     spec.End(),
+			NewText: []byte(fmt.Sprintf("(\n\t%s\n\t%s\n)", existing, strconv.Quote(pkgPath))),
+		}
+	}
+	pos := file.Name.End()
+	return analysis.TextEdit{Pos: pos, End: pos, NewText: []byte("\n\nimport " + strconv.Quote(pkgPath))}
+}
+
+// lookup returns the object name refers to at pos, or nil if it isn't
+// declared there.
+func lookup(pass *analysis.Pass, pos token.Pos, name string) types.Object {
+	scope := pass.Pkg.Scope().Innermost(pos)
+	if scope == nil {
+		return nil
+	}
+	_, obj := scope.LookupParent(name, pos)
+	return obj
+}
+
+// derefComparison returns the source of expr with both operands dereferenced.
+func derefComparison(expr *ast.BinaryExpr) string {
+	return fmt.Sprintf("%s %s %s", derefOperand(expr.X), expr.Op, derefOperand(expr.Y))
+}
+
+// derefOperand returns the source of the pointer operand expr dereferenced,
+// cancelling out & rather than adding to it: *p for both p and &*p, and x for
+// &x.
+func derefOperand(expr ast.Expr) string {
+	inner := readdressed(expr)
+	if addr, ok := inner.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+		return types.ExprString(addr.X)
+	}
+	return "*" + types.ExprString(inner)
+}
+
+// derefEdits returns the edits dereferencing the pointer operand expr the way
+// derefOperand does, keeping the source of what remains as it is.
+func derefEdits(expr ast.Expr) []analysis.TextEdit {
+	inner, prefix := readdressed(expr), "*"
+	if addr, ok := inner.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+		inner, prefix = addr.X, ""
+	}
+	var edits []analysis.TextEdit
+	if prefix != "" || inner.Pos() != expr.Pos() {
+		edits = append(edits, analysis.TextEdit{Pos: expr.Pos(), End: inner.Pos(), NewText: []byte(prefix)})
+	}
+	if inner.End() != expr.End() {
+		edits = append(edits, analysis.TextEdit{Pos: inner.End(), End: expr.End()})
+	}
+	return edits
+}
+
+// fixModeValue is the flag.Value behind -fix-mode, rejecting unknown modes
+// when flags are parsed.
+type fixModeValue string
+
+func (m *fixModeValue) String() string {
+	if m == nil || *m == "" {
+		return FixDerefBoth
+	}
+	return string(*m)
+}
+
+func (m *fixModeValue) Set(mode string) error {
+	if err := validateFixMode(mode); err != nil {
+		return err
+	}
+	*m = fixModeValue(mode)
+	return nil
+}
+
+// Choices returns the valid fix modes, for -config-schema.
+func (m *fixModeValue) Choices() []string {
+	return slices.Clone(fixModes)
+}
@@ -0,0 +1,292 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Options configures the analyzer. The zero value matches the defaults of the
+// command line flags.
+type Options struct {
+	// Rules overrides the default enabled state of rules by ID, see Rules.
+	Rules map[string]bool
+	// IfHint enables the if-hint rule unless Rules says otherwise.
+	IfHint bool
+	// LoopHint enables the loop-hint rule unless Rules says otherwise.
+	LoopHint bool
+	// IncludeOrdered enables the ordered rule unless Rules says otherwise,
+	// checking ordered comparisons (<, <=, >, >=) of pointers to basic types
+	// and, with the unsafe rule, of addresses converted to uintptr.
+	IncludeOrdered bool
+	// CheckTests also checks comparisons in _test.go files.
+	CheckTests bool
+	// ExportedOnly only checks comparisons inside exported functions and
+	// methods, including closures declared in them, for library authors
+	// concerned with the behavior of their public API.
+	ExportedOnly bool
+	// IgnoreTypes lists element types whose pointers are never reported,
+	// qualified by import path, e.g. "time.Duration" or
+	// "example.com/units.Meters", whatever name the package is imported as.
+	IgnoreTypes []string
+	// Kinds restricts reporting to pointers to these basic kinds, e.g.
+	// "int" or "string". Empty means every basic kind.
+	Kinds []string
+	// FlagNil enables the nil rule unless Rules says otherwise.
+	FlagNil bool
+	// FlagNilIn restricts the nil rule to comparisons inside functions whose
+	// name matches one of these path.Match patterns, e.g. "New*", and enables
+	// it unless Rules says otherwise. Methods match as their name or as
+	// Type.Method, and closures as the function declaring them.
+	FlagNilIn []string
+	// CheckUnsafe enables the unsafe rule unless Rules says otherwise.
+	CheckUnsafe bool
+	// CheckBoxed enables the boxed rule unless Rules says otherwise.
+	CheckBoxed bool
+	// FlagValueCompareOfIdentityTypes enables the identity rule unless Rules
+	// says otherwise. It is the inverse of the other rules, for code where
+	// some types are compared by identity, e.g. interned values or cache
+	// entries, so it is off by default and only reports the types listed in
+	// IdentityTypes.
+	FlagValueCompareOfIdentityTypes bool
+	// IdentityTypes lists the types, qualified by import path, whose
+	// values are compared by identity: comparing the values behind two
+	// pointers to one of them, *a == *b, is reported by the identity rule.
+	IdentityTypes []string
+	// FixMode selects the suggested fix for pointer comparisons: FixDerefBoth
+	// (the default when empty), FixNone or FixComment.
+	FixMode string
+	// IdentityHelper is a function, qualified by import path, e.g.
+	// "example.com/ptrutil.SamePtr", called with both operands to mark a
+	// pointer comparison as intentional. When set, the suggested fix rewrites
+	// a == b to SamePtr(a, b) and a != b to !SamePtr(a, b), importing the
+	// package if needed, instead of the fix FixMode selects. FixNone and
+	// NoSuggestions still attach no fix.
+	IdentityHelper string
+	// NoSuggestions still reports findings but never attaches suggested fixes,
+	// whatever FixMode says. For codebases where identity comparisons are
+	// often intentional and "did you mean *a == *b" would mislead.
+	NoSuggestions bool
+	// ExplainFix appends the comparison with both operands dereferenced to
+	// the message of pointer comparisons, as guidance for people reading it
+	// rather than an edit for tools to apply.
+	ExplainFix bool
+	// ReportOperator names the comparison operator in messages, e.g.
+	// "comparing pointers to basic types with '!=': int and int".
+	ReportOperator bool
+	// Logger receives a debug record for every binary expression visited,
+	// with its operand types and whether it was reported or why not, to
+	// diagnose missing or unexpected findings. Nil disables logging.
+	Logger *slog.Logger
+}
+
+// RegisterFlags registers a command line flag for every option on fs.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var((*ruleSet)(&o.Rules), "rules", "comma separated rules to enable (+id) or disable (-id), or a plain list of the only rules to enable")
+	fs.BoolVar(&o.IfHint, "if-hint", false, "hint when a pointer comparison selects between constants in an if/else, same as -rules=+if-hint")
+	fs.BoolVar(&o.LoopHint, "loop-hint", false, "hint when a pointer comparison searches a range loop over a slice of pointers, same as -rules=+loop-hint")
+	fs.BoolVar(&o.IncludeOrdered, "include-ordered", false, "also check ordered comparisons (<, <=, >, >=), same as -rules=+ordered")
+	fs.BoolVar(&o.CheckTests, "check-tests", false, "also check _test.go files")
+	fs.BoolVar(&o.ExportedOnly, "exported-only", false, "only check comparisons inside exported functions and methods")
+	fs.Var((*listValue)(&o.IgnoreTypes), "ignore-types", "comma separated element types whose pointers are never reported, e.g. time.Duration")
+	fs.Var((*listValue)(&o.Kinds), "kinds", "comma separated basic kinds to report, e.g. int,string (default all)")
+	fs.BoolVar(&o.FlagNil, "flag-nil", false, "also report comparisons of pointers to basic types with nil, same as -rules=+nil")
+	fs.Var((*listValue)(&o.FlagNilIn), "flag-nil-in", "comma separated function name patterns to report nil comparisons in, e.g. New*,Cache.Get, implies -flag-nil")
+	fs.Var((*fixModeValue)(&o.FixMode), "fix-mode", "suggested fix for pointer comparisons: deref-both, none or comment")
+	fs.StringVar(&o.IdentityHelper, "identity-helper", "", "function qualified by import path, e.g. example.com/ptrutil.SamePtr, that the suggested fix calls with both operands instead of comparing them")
+	fs.BoolVar(&o.NoSuggestions, "no-suggestions", false, "report findings without suggested fixes, same as -fix-mode=none")
+	fs.BoolVar(&o.ExplainFix, "explain-fix", false, "append the comparison with both operands dereferenced to messages, e.g. \"; consider: *a == *b\"")
+	fs.BoolVar(&o.CheckBoxed, "check-boxed", false, "also report comparisons of interfaces known to hold pointers to basic types, same as -rules=+boxed")
+	fs.BoolVar(&o.FlagValueCompareOfIdentityTypes, "flag-value-compare-of-identity-types", false, "report comparisons of the values behind pointers to -identity-types, e.g. *a == *b, same as -rules=+identity")
+	fs.Var((*listValue)(&o.IdentityTypes), "identity-types", "comma separated types compared by identity for the identity rule, e.g. example.com/cache.Entry")
+	fs.BoolVar(&o.ReportOperator, "report-operator", false, "name the comparison operator in messages, e.g. \"comparing pointers to basic types with '!='\"")
+	fs.BoolVar(&o.CheckUnsafe, "check-unsafe", false, "also report address comparisons through unsafe conversions, same as -rules=+unsafe")
+}
+
+func (o *Options) enabled(id string) bool {
+	if on, ok := o.Rules[id]; ok {
+		return on
+	}
+	switch id {
+	case "ordered":
+		return o.IncludeOrdered
+	case "if-hint":
+		return o.IfHint
+	case "loop-hint":
+		return o.LoopHint
+	case "nil":
+		return o.FlagNil || len(o.FlagNilIn) > 0
+	case "unsafe":
+		return o.CheckUnsafe
+	case "boxed":
+		return o.CheckBoxed
+	case "identity":
+		return o.FlagValueCompareOfIdentityTypes
+	}
+	r, _ := lookupRule(id)
+	return r.Default
+}
+
+// fixMode returns the effective FixMode, taking NoSuggestions into account.
+func (o *Options) fixMode() string {
+	if o.NoSuggestions {
+		return FixNone
+	}
+	return o.FixMode
+}
+
+// validateNilScopes checks the patterns of FlagNilIn.
+func (o *Options) validateNilScopes() error {
+	for _, pattern := range o.FlagNilIn {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid -flag-nil-in pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// nilInScope reports whether the nil rule applies in the function at the
+// top of stack.
+func (o *Options) nilInScope(stack []ast.Node) bool {
+	if len(o.FlagNilIn) == 0 {
+		return true
+	}
+	names := functionNames(stack)
+	return slices.ContainsFunc(o.FlagNilIn, func(pattern string) bool {
+		return slices.ContainsFunc(names, func(name string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		})
+	})
+}
+
+// inExportedFunction reports whether the innermost function declaration in
+// stack has an exported name.
+func inExportedFunction(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if decl, ok := stack[i].(*ast.FuncDecl); ok {
+			return decl.Name.IsExported()
+		}
+	}
+	return false
+}
+
+// functionNames returns the names the innermost function declaration in
+// stack is matched by: its name, and Type.Method for methods.
+func functionNames(stack []ast.Node) []string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		decl, ok := stack[i].(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		names := []string{decl.Name.Name}
+		if decl.Recv != nil && len(decl.Recv.List) == 1 {
+			recv := ast.Unparen(decl.Recv.List[0].Type)
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = ast.Unparen(star.X)
+			}
+			switch generic := recv.(type) {
+			case *ast.IndexExpr:
+				recv = generic.X
+			case *ast.IndexListExpr:
+				recv = generic.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				names = append(names, ident.Name+"."+decl.Name.Name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// kindSet resolves Kinds to basic kinds, or nil when every kind is allowed.
+// Names are looked up in the universe scope, so the aliases byte and rune
+// select the same kinds as uint8 and int32.
+func (o *Options) kindSet() (map[types.BasicKind]bool, error) {
+	if len(o.Kinds) == 0 {
+		return nil, nil
+	}
+	kinds := make(map[types.BasicKind]bool, len(o.Kinds))
+	for _, name := range o.Kinds {
+		typeName, _ := types.Universe.Lookup(name).(*types.TypeName)
+		if typeName == nil {
+			return nil, fmt.Errorf("unknown basic kind %q", name)
+		}
+		basic, ok := typeName.Type().(*types.Basic)
+		if !ok {
+			return nil, fmt.Errorf("unknown basic kind %q", name)
+		}
+		kinds[basic.Kind()] = true
+	}
+	return kinds, nil
+}
+
+// reportable reports whether pointers to t should be reported given the basic
+// kinds allowed and the types ignored.
+func (o *Options) reportable(t types.Type, kinds map[types.BasicKind]bool) bool {
+	basics, ok := basicTypes(t)
+	if !ok {
+		return false
+	}
+	if kinds != nil && !slices.ContainsFunc(basics, func(b *types.Basic) bool { return kinds[b.Kind()] }) {
+		return false
+	}
+	return !slices.Contains(o.IgnoreTypes, qualifiedName(t))
+}
+
+// qualifiedName returns the name of t qualified by full import paths, e.g.
+// "example.com/units.Meters", which IgnoreTypes and IdentityTypes are matched
+// against. It doesn't depend on how the package being analyzed imports the
+// type's package, so aliased and dot imports match the same entries.
+func qualifiedName(t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+// listValue is a flag.Value holding a comma separated list.
+type listValue []string
+
+func (l *listValue) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+// Type reports the flag as a list, for -config-schema.
+func (l *listValue) Type() string {
+	return "list"
+}
+
+func (l *listValue) Set(s string) error {
+	*l = nil
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
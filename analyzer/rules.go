@@ -0,0 +1,127 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rule is a sub-check that can be enabled or disabled independently.
+type Rule struct {
+	ID      string
+	Doc     string
+	Default bool
+}
+
+// Rules lists every sub-check that can be toggled with Options.Rules. Every
+// diagnostic carries the ID of the rule that produced it in its Category.
+var Rules = []Rule{
+	{ID: "same-type", Doc: "comparisons between pointers to the same basic type", Default: true},
+	{ID: "cross-type", Doc: "comparisons between pointers to different basic types (only reachable in code that fails to type-check)", Default: true},
+	{ID: "address-of", Doc: "comparisons between pointers to the same basic type where an operand takes an address with &, e.g. p == &x", Default: true},
+	{ID: "self", Doc: "comparisons of a pointer, or the address of a variable, with itself, e.g. p == p, which are always true or always false", Default: true},
+	{ID: "ordered", Doc: "ordered comparisons (<, <=, >, >=) between pointers to basic types, which don't compile and usually meant to compare the values, or with unsafe of addresses converted to uintptr", Default: false},
+	{ID: "nil", Doc: "comparisons between a pointer to a basic type and nil", Default: false},
+	{ID: "unsafe", Doc: "comparisons of pointers to basic types converted to uintptr, e.g. uintptr(unsafe.Pointer(p)) == uintptr(unsafe.Pointer(q))", Default: false},
+	{ID: "boxed", Doc: "comparisons of interfaces known to hold pointers to basic types, e.g. any(p) == any(q)", Default: false},
+	{ID: "if-hint", Doc: "hint when the comparison is the sole condition of an if/else whose branches differ only by a constant", Default: false},
+	{ID: "loop-hint", Doc: "hint when the comparison searches a range loop over a slice or array of pointers for another pointer", Default: false},
+	{ID: "identity", Doc: "comparisons of the values behind pointers to the identity types configured, e.g. *a == *b, where a == b was likely meant", Default: false},
+}
+
+func lookupRule(id string) (Rule, bool) {
+	i := slices.IndexFunc(Rules, func(r Rule) bool { return r.ID == id })
+	if i < 0 {
+		return Rule{}, false
+	}
+	return Rules[i], true
+}
+
+// ParseRules parses a -rules value into the overrides for Options.Rules.
+// Entries prefixed with + or - enable or disable a rule relative to the
+// defaults, e.g. "+if-hint,-cross-type". If the first entry has no prefix the
+// value is a list of the only rules to enable, e.g. "same-type,if-hint".
+func ParseRules(spec string) (map[string]bool, error) {
+	enabled := make(map[string]bool)
+	if spec == "" {
+		return enabled, nil
+	}
+
+	entries := strings.Split(spec, ",")
+	if first := strings.TrimSpace(entries[0]); !strings.HasPrefix(first, "+") && !strings.HasPrefix(first, "-") {
+		for _, r := range Rules {
+			enabled[r.ID] = false
+		}
+	}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		on := !strings.HasPrefix(entry, "-")
+		id := strings.TrimLeft(entry, "+-")
+		if _, ok := lookupRule(id); !ok {
+			return nil, fmt.Errorf("unknown rule %q in -rules, see -list-rules", id)
+		}
+		enabled[id] = on
+	}
+	return enabled, nil
+}
+
+// ruleSet is the flag.Value behind -rules.
+type ruleSet map[string]bool
+
+func (s *ruleSet) String() string {
+	if s == nil {
+		return ""
+	}
+	var entries []string
+	for _, r := range Rules {
+		if on, ok := (*s)[r.ID]; ok {
+			if on {
+				entries = append(entries, "+"+r.ID)
+			} else {
+				entries = append(entries, "-"+r.ID)
+			}
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+// Type reports the flag as a list, for -config-schema.
+func (s *ruleSet) Type() string {
+	return "list"
+}
+
+// Choices returns the IDs of the rules the list may name, for -config-schema.
+func (s *ruleSet) Choices() []string {
+	ids := make([]string, len(Rules))
+	for i, r := range Rules {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func (s *ruleSet) Set(spec string) error {
+	enabled, err := ParseRules(spec)
+	if err != nil {
+		return err
+	}
+	*s = enabled
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package analyzer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	enabled, err := ParseRules("")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{}, enabled)
+
+	enabled, err = ParseRules("+if-hint,-cross-type")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"cross-type": false, "if-hint": true}, enabled)
+
+	enabled, err = ParseRules("if-hint")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"same-type": false, "cross-type": false, "address-of": false, "self": false, "ordered": false, "nil": false, "unsafe": false, "boxed": false, "if-hint": true, "loop-hint": false, "identity": false}, enabled)
+
+	_, err = ParseRules("+unordered")
+	assert.NotNil(t, err)
+}
+
+func TestEnabled(t *testing.T) {
+	opts := Options{}
+	assert.True(t, opts.enabled("same-type"))
+	assert.False(t, opts.enabled("nil"))
+	assert.False(t, opts.enabled("if-hint"))
+
+	opts = Options{FlagNil: true, IfHint: true, Rules: map[string]bool{"if-hint": false}}
+	assert.True(t, opts.enabled("nil"))
+	assert.False(t, opts.enabled("if-hint"))
+}
+
+func TestRegisterFlags(t *testing.T) {
+	ptrAnalyzer := NewPtrAnalyzer()
+	err := ptrAnalyzer.Flags.Parse([]string{"-rules=-cross-type", "-kinds=int, string", "-ignore-types=time.Duration", "-check-tests", "-flag-nil"})
+	assert.Nil(t, err)
+	assert.Equal(t, "-cross-type", ptrAnalyzer.Flags.Lookup("rules").Value.String())
+	assert.Equal(t, "int,string", ptrAnalyzer.Flags.Lookup("kinds").Value.String())
+	assert.Equal(t, "time.Duration", ptrAnalyzer.Flags.Lookup("ignore-types").Value.String())
+
+	ptrAnalyzer.Flags.SetOutput(io.Discard)
+	err = ptrAnalyzer.Flags.Parse([]string{"-rules=+bogus"})
+	assert.NotNil(t, err)
+}
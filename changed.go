@@ -0,0 +1,155 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// onlyChangedDirs returns the directories of the packages to analyze for
+// -only-changed-packages: those containing the files listed in changedFiles,
+// a comma separated list, or if it is empty the files changed since the git
+// revision since. It fails if the changes can't be determined or may affect
+// every package.
+func onlyChangedDirs(ctx context.Context, dir, since, changedFiles string) ([]string, error) {
+	var files []string
+	if changedFiles != "" {
+		for _, name := range strings.Split(changedFiles, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				files = append(files, name)
+			}
+		}
+	} else {
+		var err error
+		if files, err = gitChangedFiles(ctx, dir, since); err != nil {
+			return nil, err
+		}
+	}
+	dirs, ok, err := changedPackageDirs(dir, files)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("go.mod, go.sum or go.work changed")
+	}
+	return dirs, nil
+}
+
+// gitChangedFiles returns the absolute paths of the files under dir that
+// differ from the git revision since, including uncommitted changes and new
+// files git doesn't ignore.
+func gitChangedFiles(ctx context.Context, dir, since string) ([]string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := gitFiles(ctx, root, "diff", "--name-only", "--relative", since, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitFiles(ctx, root, "ls-files", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return append(changed, untracked...), nil
+}
+
+// gitFiles runs git with args in root and returns the absolute paths of the
+// files it lists, one per line relative to root.
+func gitFiles(ctx context.Context, root string, args ...string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	var files []string
+	for _, name := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if name != "" {
+			files = append(files, filepath.Join(root, filepath.FromSlash(name)))
+		}
+	}
+	return files, nil
+}
+
+// changedPackageDirs returns the directories under dir of the packages that
+// contain the changed files, which may be relative to the working directory.
+// Files that aren't Go files are ignored, except for go.mod, go.sum and
+// go.work: changing those can affect every package, so ok is false and every
+// package should be analyzed. Directories without Go files left, e.g. after
+// their package was deleted, are skipped.
+func changedPackageDirs(dir string, files []string) (dirs []string, ok bool, err error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	dirs = []string{}
+	for _, name := range files {
+		switch filepath.Base(name) {
+		case "go.mod", "go.sum", "go.work", "go.work.sum":
+			return nil, false, nil
+		}
+		if filepath.Ext(name) != ".go" {
+			continue
+		}
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, false, err
+		}
+		pkgDir := filepath.Dir(abs)
+		if rel, err := filepath.Rel(root, pkgDir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if goFiles, _ := filepath.Glob(filepath.Join(pkgDir, "*.go")); len(goFiles) == 0 {
+			continue
+		}
+		if !slices.Contains(dirs, pkgDir) {
+			dirs = append(dirs, pkgDir)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs, true, nil
+}
+
+// changedPatterns returns the package patterns relative to dir that load the
+// packages in changedDirs.
+func changedPatterns(dir string, changedDirs []string) ([]string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	patterns := make([]string, 0, len(changedDirs))
+	for _, pkgDir := range changedDirs {
+		rel, err := filepath.Rel(root, pkgDir)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, "./"+filepath.ToSlash(rel))
+	}
+	return patterns, nil
+}
@@ -0,0 +1,103 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChangedPackageDirs(t *testing.T) {
+	root, err := filepath.Abs("./testdata/src/multipkg")
+	assert.Nil(t, err)
+
+	dirs, ok, err := changedPackageDirs(root, []string{
+		"testdata/src/multipkg/second/second.go",
+		"testdata/src/multipkg/first/first.go",
+		"testdata/src/multipkg/first/README.md",
+		"testdata/src/multipkg/second/second.go",
+		"testdata/src/multipkg/deleted/deleted.go",
+		"testdata/src/other/other.go",
+	})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{filepath.Join(root, "first"), filepath.Join(root, "second")}, dirs)
+
+	dirs, ok, err = changedPackageDirs(root, []string{"README.md"})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{}, dirs)
+
+	_, ok, err = changedPackageDirs(root, []string{"testdata/src/multipkg/first/first.go", "go.mod"})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestOnlyChangedPackages(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-only-changed-packages", "-changed-files=testdata/src/multipkg/second/second.go", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 2, strings.Count(stdout.String(), "second.go"))
+	assert.False(t, strings.Contains(stdout.String(), "first.go"))
+
+	stdout.Reset()
+	code = run([]string{"-only-changed-packages", "-changed-files=testdata/src/multipkg/notes.txt", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+
+	// Without a usable revision every package is analyzed.
+	stdout.Reset()
+	code = run([]string{"-only-changed-packages", "-since=no-such-revision", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 4, strings.Count(stdout.String(), "\n"))
+	assert.True(t, strings.Contains(stderr.String(), "can't tell which packages changed, analyzing every package"))
+
+	stderr.Reset()
+	code = run([]string{"-since=HEAD~1", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.Contains(stderr.String(), "-since and -changed-files require -only-changed-packages"))
+}
+
+func TestGitChangedFilesIncludesUntracked(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		assert.Nil(t, err, string(out))
+	}
+	git("init", "-q")
+	assert.Nil(t, os.WriteFile(filepath.Join(repo, "committed.go"), []byte("package p\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(repo, ".gitignore"), []byte("ignored.go\n"), 0o644))
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	assert.Nil(t, os.WriteFile(filepath.Join(repo, "committed.go"), []byte("package p\n\nvar x int\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(repo, "untracked.go"), []byte("package p\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(repo, "ignored.go"), []byte("package p\n"), 0o644))
+
+	files, err := gitChangedFiles(context.Background(), repo, "HEAD")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(repo, "committed.go"), filepath.Join(repo, "untracked.go")}, files)
+}
@@ -0,0 +1,42 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+
+// Command ptrcmp-suite shows ptrcmp running next to other analyzers in a
+// single multichecker binary, the way a suite of custom analyzers would
+// include it:
+//
+//	go run ./cmd/ptrcmp-suite ./...
+//
+// Each analyzer's flags are prefixed with its name, e.g. -ptrcmp.rules.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+	"golang.org/x/tools/go/analysis/passes/nilness"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"ptrcomp/analyzer"
+)
+
+func main() {
+	multichecker.Main(
+		nilness.Analyzer,
+		printf.Analyzer,
+		analyzer.NewPtrAnalyzer(),
+	)
+}
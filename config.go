@@ -0,0 +1,167 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// configFileName is the file in the working directory setting flags that
+// aren't given on the command line, written by -init.
+const configFileName = ".ptrcmp.yaml"
+
+// commandFlags do something other than configure a run, so they can't be set
+// in the config file.
+var commandFlags = []string{"config-schema", "force", "init", "list-rules"}
+
+// plainValue matches values written to the config file without quotes.
+var plainValue = regexp.MustCompile(`^[A-Za-z0-9_./,+-]+$`)
+
+// writeConfigTemplate writes a config file to w setting every flag in flags
+// except commandFlags to its default, commented out, under its usage.
+func writeConfigTemplate(w io.Writer, flags *flag.FlagSet) error {
+	var out strings.Builder
+	out.WriteString("# ptrcmp configuration. Uncomment a setting to change it; flags given on the\n")
+	out.WriteString("# command line take precedence. Lists are comma separated, as for the flags.\n")
+	flags.VisitAll(func(f *flag.Flag) {
+		if slices.Contains(commandFlags, f.Name) {
+			return
+		}
+		value := f.DefValue
+		if !plainValue.MatchString(value) {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&out, "\n# %s\n# %s: %s\n", f.Usage, f.Name, value)
+	})
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// writeConfigFile writes the config file template to the working directory,
+// refusing to replace an existing file unless force is set.
+func writeConfigFile(flags *flag.FlagSet, force bool) error {
+	mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(configFileName, mode, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", configFileName)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeConfigTemplate(f, flags); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// applyConfigFile sets the flags in flags listed in the config file in the
+// working directory, if there is one, except those already set on the
+// command line. Each line is blank, a # comment or "name: value", where value
+// may be double or single quoted and unquoted values may be followed by a
+// comment.
+func applyConfigFile(flags *flag.FlagSet) error {
+	f, err := os.Open(configFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	defer f.Close()
+
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, err := parseConfigLine(line)
+		if err == nil && (flags.Lookup(name) == nil || slices.Contains(commandFlags, name)) {
+			err = fmt.Errorf("unknown setting %q", name)
+		}
+		if err == nil && !explicit[name] {
+			err = flags.Set(name, value)
+		}
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", configFileName, n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	return nil
+}
+
+// parseConfigLine parses a "name: value" line of the config file.
+func parseConfigLine(line string) (name, value string, err error) {
+	name, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", fmt.Errorf("expected name: value, got %q", line)
+	}
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	var rest string
+	switch {
+	case strings.HasPrefix(value, `"`):
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		rest = value[len(quoted):]
+		value, _ = strconv.Unquote(quoted)
+	case strings.HasPrefix(value, "'"):
+		end := 1
+		for {
+			i := strings.IndexByte(value[end:], '\'')
+			if i < 0 {
+				return "", "", fmt.Errorf("invalid quoted value %s", value)
+			}
+			end += i + 1
+			if !strings.HasPrefix(value[end:], "'") {
+				break
+			}
+			end++
+		}
+		rest = value[end:]
+		value = strings.ReplaceAll(value[1:end-1], "''", "'")
+	default:
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", "", fmt.Errorf("unexpected %q after quoted value", rest)
+	}
+	return name, value, nil
+}
@@ -0,0 +1,109 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitConfig(t *testing.T) {
+	tests, err := filepath.Abs("tests")
+	assert.Nil(t, err)
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-init"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	written, err := os.ReadFile(configFileName)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(written), "\n# output format: text, json, sarif, junit or template\n# format: text\n"))
+	assert.True(t, strings.Contains(string(written), "\n# kinds: \"\"\n"))
+	assert.False(t, strings.Contains(string(written), "# init:"))
+
+	// The template as written changes nothing.
+	code = run([]string{tests}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "with_pointer_comparison.go:25:5: comparing pointers to basic types: int and int"))
+
+	code = run([]string{"-init"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.Contains(stderr.String(), ".ptrcmp.yaml already exists, use -force to overwrite it"))
+
+	assert.Nil(t, os.WriteFile(configFileName, []byte("# settings\nstrict-exit: true\nkinds: 'string' # no ints\nformat: \"json\"\n"), 0o644))
+	stdout.Reset()
+	code = run([]string{tests}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "[]\n", stdout.String())
+
+	// Flags on the command line take precedence.
+	stdout.Reset()
+	code = run([]string{"-kinds", "int", "-format", "text", tests}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.Contains(stdout.String(), "with_pointer_comparison.go:25:5"))
+
+	code = run([]string{"-init", "-force"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	rewritten, err := os.ReadFile(configFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, written, rewritten)
+
+	for config, want := range map[string]string{
+		"colour: always\n":        `.ptrcmp.yaml:1: unknown setting "colour"`,
+		"\ninit: true\n":          `.ptrcmp.yaml:2: unknown setting "init"`,
+		"color sometimes\n":       `.ptrcmp.yaml:1: expected name: value`,
+		"fix-mode: 'sometimes'\n": `.ptrcmp.yaml:1: invalid fix mode "sometimes"`,
+	} {
+		assert.Nil(t, os.WriteFile(configFileName, []byte(config), 0o644))
+		stderr.Reset()
+		code = run([]string{tests}, &stdout, &stderr)
+		assert.Equal(t, 1, code, config)
+		assert.True(t, strings.Contains(stderr.String(), want), stderr.String())
+	}
+
+	code = run([]string{"-force", tests}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+}
+
+func TestParseConfigLine(t *testing.T) {
+	for line, want := range map[string]string{
+		"since: origin/main":          "origin/main",
+		"since: origin/main # branch": "origin/main",
+		`since: "a#b" # comment`:      "a#b",
+		`since: "say \"hi\""`:         `say "hi"`,
+		"since: 'it''s'":              "it's",
+		"since: ''":                   "",
+		"since:":                      "",
+	} {
+		name, value, err := parseConfigLine(line)
+		assert.Nil(t, err, line)
+		assert.Equal(t, "since", name)
+		assert.Equal(t, want, value, line)
+	}
+	for _, line := range []string{"since", `since: "open`, "since: 'open", `since: "a" b`} {
+		_, _, err := parseConfigLine(line)
+		assert.NotNil(t, err, line)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// textEdit replaces the bytes from Start to End of a file with NewText.
+type textEdit struct {
+	Start, End int
+	NewText    string
+}
+
+// writeDiff prints a unified diff, applicable with git apply, of the first
+// suggested fix of every finding, reading the files with readFile. Edits
+// overlapping an earlier edit in the same file are dropped. Paths are relative
+// to the working directory when possible so the diff applies from there.
+func writeDiff(w io.Writer, findings []finding, readFile func(name string) ([]byte, error)) error {
+	var files []string
+	edits := make(map[string][]textEdit)
+	for _, f := range findings {
+		if len(f.Fix) == 0 {
+			continue
+		}
+		if _, ok := edits[f.Pos.Filename]; !ok {
+			files = append(files, f.Pos.Filename)
+		}
+		edits[f.Pos.Filename] = append(edits[f.Pos.Filename], f.Fix...)
+	}
+
+	for _, name := range files {
+		before, err := readFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s for -diff: %v", name, err)
+		}
+		after := applyEdits(string(before), edits[name])
+		fmt.Fprint(w, unifiedDiff(diffPath(name), string(before), after))
+	}
+	return nil
+}
+
+// applyEdits returns src with edits applied, skipping any edit that overlaps
+// one before it or is identical to it, e.g. the same import added by the fixes
+// of several findings.
+func applyEdits(src string, edits []textEdit) string {
+	slices.SortStableFunc(edits, func(a, b textEdit) int { return a.Start - b.Start })
+	var out strings.Builder
+	last := 0
+	var applied []textEdit
+	for _, e := range edits {
+		if e.Start < last || e.End > len(src) || slices.Contains(applied, e) {
+			continue
+		}
+		out.WriteString(src[last:e.Start])
+		out.WriteString(e.NewText)
+		last = e.End
+		applied = append(applied, e)
+	}
+	out.WriteString(src[last:])
+	return out.String()
+}
+
+// diffPath returns name relative to the working directory, or name itself if
+// it is outside it.
+func diffPath(name string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	return filepath.ToSlash(relativePath(wd, name))
+}
+
+// unifiedDiff returns the unified diff turning before into after, or "" if
+// they are equal. Only fixes adding an import or a -fix-mode=comment TODO add
+// lines, so lines are compared pairwise; if the line counts differ the whole
+// file is a single hunk.
+func unifiedDiff(name, before, after string) string {
+	if before == after {
+		return ""
+	}
+	oldLines, newLines := splitLines(before), splitLines(after)
+
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	if len(oldLines) != len(newLines) {
+		hunks = []hunk{{0, max(len(oldLines), len(newLines))}}
+	} else {
+		for i := range oldLines {
+			if oldLines[i] == newLines[i] {
+				continue
+			}
+			start, end := max(i-diffContext, 0), min(i+diffContext+1, len(oldLines))
+			if n := len(hunks); n > 0 && start <= hunks[n-1].end {
+				hunks[n-1].end = end
+			} else {
+				hunks = append(hunks, hunk{start, end})
+			}
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+	for _, h := range hunks {
+		oldEnd, newEnd := min(h.end, len(oldLines)), min(h.end, len(newLines))
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(h.start, oldEnd), hunkRange(h.start, newEnd))
+		if len(oldLines) != len(newLines) {
+			writeLines(&out, "-", oldLines)
+			writeLines(&out, "+", newLines)
+			continue
+		}
+		for i := h.start; i < h.end; {
+			if oldLines[i] == newLines[i] {
+				writeLines(&out, " ", oldLines[i:i+1])
+				i++
+				continue
+			}
+			j := i + 1
+			for j < h.end && oldLines[j] != newLines[j] {
+				j++
+			}
+			writeLines(&out, "-", oldLines[i:j])
+			writeLines(&out, "+", newLines[i:j])
+			i = j
+		}
+	}
+	return out.String()
+}
+
+// hunkRange formats the lines from start to end, 0-based and exclusive, as a
+// unified diff range.
+func hunkRange(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
+
+// splitLines splits s after every newline. The last line lacks one if s
+// doesn't end with a newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func writeLines(out *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		out.WriteString(prefix + line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
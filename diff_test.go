@@ -0,0 +1,71 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	after := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\neleven\n12\n"
+	assert.Equal(t, "--- a/f.go\n+++ b/f.go\n"+
+		"@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n"+
+		"@@ -8,5 +8,5 @@\n 8\n 9\n 10\n-11\n+eleven\n 12\n",
+		unifiedDiff("f.go", before, after))
+
+	assert.Equal(t, "--- a/f.go\n+++ b/f.go\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		unifiedDiff("f.go", "a\nb", "a\nc"))
+	assert.Equal(t, "", unifiedDiff("f.go", "a\n", "a\n"))
+}
+
+func TestApplyEditsSkipsOverlaps(t *testing.T) {
+	edits := []textEdit{{Start: 4, End: 4, NewText: "*"}, {Start: 0, End: 0, NewText: "*"}, {Start: 2, End: 6, NewText: "!"}}
+	assert.Equal(t, "*a !", applyEdits("a == b", edits))
+
+	edits = []textEdit{{Start: 1, End: 1, NewText: "x"}, {Start: 1, End: 1, NewText: "x"}, {Start: 1, End: 1, NewText: "y"}}
+	assert.Equal(t, "axyb", applyEdits("ab", edits))
+}
+
+func TestDiffApplies(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	for _, mode := range []string{"deref-both", "comment"} {
+		var stdout, stderr strings.Builder
+		code := run([]string{"-diff", "-fix-mode", mode, "./testdata/src/fixes"}, &stdout, &stderr)
+		assert.Equal(t, 0, code)
+		assert.True(t, strings.HasPrefix(stdout.String(), "--- a/testdata/src/fixes/fixes.go\n+++ b/testdata/src/fixes/fixes.go\n"))
+
+		patch := filepath.Join(t.TempDir(), "fixes.patch")
+		assert.Nil(t, os.WriteFile(patch, []byte(stdout.String()), 0o644))
+		out, err := exec.Command("git", "apply", "--check", patch).CombinedOutput()
+		assert.Nil(t, err, string(out))
+	}
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-diff", "./testdata/src/fixes"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "+\t_ = *a == *b"))
+}
@@ -0,0 +1,110 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"ptrcomp/report"
+	"slices"
+)
+
+// readExpected reads the findings expected by -expect, in the -format=json
+// format. Relative paths are resolved against the working directory.
+func readExpected(path string) ([]report.Finding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected findings: %v", err)
+	}
+	defer f.Close()
+	expected, err := report.ReadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected findings %s: %v", path, err)
+	}
+	return expected, nil
+}
+
+// resolve returns f with an absolute filename, so that findings read from an
+// expectations file match regardless of how their paths were written.
+func resolve(f report.Finding) report.Finding {
+	if abs, err := filepath.Abs(f.Pos.Filename); err == nil {
+		f.Pos.Filename = abs
+	}
+	return f
+}
+
+// expectationKey identifies a finding for -expect by its position and message.
+func expectationKey(f report.Finding) string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+// writeExpectationDiff compares the actual findings with the expected ones,
+// printing every expected finding that is missing prefixed with "-" and every
+// unexpected one prefixed with "+", in position order. It reports whether they
+// matched. Info findings never count as failures, so they are left out on
+// both sides.
+func writeExpectationDiff(w io.Writer, expected []report.Finding, actual []finding) bool {
+	type change struct {
+		sign string
+		f    report.Finding
+	}
+	pending := make(map[string][]report.Finding)
+	for _, f := range expected {
+		if f.Severity == report.SeverityInfo {
+			continue
+		}
+		f = resolve(f)
+		key := expectationKey(f)
+		pending[key] = append(pending[key], f)
+	}
+	var changes []change
+	for _, f := range actual {
+		if f.Severity == report.SeverityInfo {
+			continue
+		}
+		resolved := resolve(f.Finding)
+		key := expectationKey(resolved)
+		if len(pending[key]) > 0 {
+			pending[key] = pending[key][1:]
+			continue
+		}
+		changes = append(changes, change{"+", resolved})
+	}
+	for _, missing := range pending {
+		for _, f := range missing {
+			changes = append(changes, change{"-", f})
+		}
+	}
+	slices.SortFunc(changes, func(a, b change) int {
+		return cmp.Or(
+			cmp.Compare(a.f.Pos.Filename, b.f.Pos.Filename),
+			cmp.Compare(a.f.Pos.Line, b.f.Pos.Line),
+			cmp.Compare(a.f.Pos.Column, b.f.Pos.Column),
+			cmp.Compare(a.f.Message, b.f.Message),
+			cmp.Compare(a.sign, b.sign),
+		)
+	})
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s%s\n", c.sign, expectationKey(c.f))
+	}
+	return len(changes) == 0
+}
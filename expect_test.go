@@ -0,0 +1,70 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpect(t *testing.T) {
+	expect := filepath.Join(t.TempDir(), "expected.json")
+	write := func(content string) {
+		assert.Nil(t, os.WriteFile(expect, []byte(content), 0o644))
+	}
+	entry := func(file string, line int, message string) string {
+		return fmt.Sprintf(`{"file": "testdata/src/multipkg/%s", "line": %d, "column": 6, "message": "comparing pointers to basic types: %s"}`, file, line, message)
+	}
+
+	write("[" + strings.Join([]string{
+		entry("first/first.go", 22, "int and int"),
+		entry("first/first.go", 26, "string and string"),
+		entry("second/second.go", 22, "int and int"),
+		entry("second/second.go", 26, "string and string"),
+	}, ",") + "]")
+	var stdout, stderr strings.Builder
+	code := run([]string{"-expect", expect, "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+
+	write("[" + strings.Join([]string{
+		entry("first/first.go", 22, "int and int"),
+		entry("first/first.go", 26, "string and string"),
+		entry("second/second.go", 24, "int and int"),
+	}, ",") + "]")
+	stdout.Reset()
+	code = run([]string{"-expect", expect, "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "+/"))
+	assert.True(t, strings.HasSuffix(lines[0], "multipkg/second/second.go:22:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.HasSuffix(lines[1], "multipkg/second/second.go:24:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.HasPrefix(lines[1], "-/"))
+	assert.True(t, strings.HasSuffix(lines[2], "multipkg/second/second.go:26:6: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(stderr.String(), "findings don't match"))
+
+	write("not json")
+	code = run([]string{"-expect", expect, "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+}
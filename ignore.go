@@ -0,0 +1,135 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ignoreFileName is the file in the analyzed directory listing findings to
+// suppress.
+const ignoreFileName = ".ptrcmpignore"
+
+// ignoreRule is an entry of the ignore file: a glob matched against the
+// slash-separated path of a finding relative to the analyzed directory,
+// optionally restricted to a single line.
+type ignoreRule struct {
+	pattern string
+	// line is the line the rule applies to, or 0 for every line.
+	line int
+}
+
+// readIgnoreFile reads the ignore file in dir, returning no rules if there is
+// none. Blank lines and lines starting with # are skipped.
+func readIgnoreFile(dir string) ([]ignoreRule, error) {
+	name := filepath.Join(dir, ignoreFileName)
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %v", err)
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		rule, err := parseIgnoreRule(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", name, n, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %v", err)
+	}
+	return rules, nil
+}
+
+// parseIgnoreRule parses an entry of the form "pattern" or "pattern:line".
+func parseIgnoreRule(entry string) (ignoreRule, error) {
+	rule := ignoreRule{pattern: entry}
+	if i := strings.LastIndex(entry, ":"); i >= 0 {
+		line, err := strconv.Atoi(entry[i+1:])
+		if err != nil || line <= 0 {
+			return ignoreRule{}, fmt.Errorf("invalid line in %q", entry)
+		}
+		rule.pattern, rule.line = entry[:i], line
+	}
+	rule.pattern = strings.TrimPrefix(rule.pattern, "./")
+	if _, err := path.Match(rule.pattern, ""); err != nil {
+		return ignoreRule{}, fmt.Errorf("invalid pattern %q: %v", rule.pattern, err)
+	}
+	return rule, nil
+}
+
+// matches reports whether the rule suppresses a finding on line of the file
+// at rel, the slash-separated path relative to the analyzed directory. Like
+// .gitignore, a pattern matching a directory matches every file below it, and
+// a pattern without a slash is matched against every path element, so
+// "*_gen.go" matches in any directory.
+func (r ignoreRule) matches(rel string, line int) bool {
+	if r.line != 0 && r.line != line {
+		return false
+	}
+	anywhere := !strings.Contains(r.pattern, "/")
+	for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+		target := p
+		if anywhere {
+			target = path.Base(p)
+		}
+		if ok, _ := path.Match(r.pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored reports whether any of rules suppresses a finding at pos, given the
+// absolute path of the directory the rules were read from.
+func ignored(rules []ignoreRule, root string, pos token.Position) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, pos.Filename)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, r := range rules {
+		if r.matches(rel, pos.Line) {
+			return true
+		}
+	}
+	return false
+}
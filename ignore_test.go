@@ -0,0 +1,85 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"ptrcomp/analyzer"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreRuleMatches(t *testing.T) {
+	tests := []struct {
+		entry string
+		rel   string
+		line  int
+		want  bool
+	}{
+		{"foo.go:12", "foo.go", 12, true},
+		{"foo.go:12", "foo.go", 13, false},
+		{"foo.go:12", "pkg/foo.go", 12, true},
+		{"pkg/foo.go:12", "pkg/foo.go", 12, true},
+		{"pkg/foo.go:12", "other/pkg/foo.go", 12, false},
+		{"./pkg/foo.go", "pkg/foo.go", 3, true},
+		{"*_gen.go", "a/b/table_gen.go", 40, true},
+		{"*_gen.go", "a/b/table.go", 40, false},
+		{"pkg/*.go", "pkg/foo.go", 1, true},
+		{"pkg/*.go", "pkg/sub/foo.go", 1, false},
+		{"third_party", "third_party/lib/lib.go", 7, true},
+		{"internal/third_party", "third_party/lib/lib.go", 7, false},
+	}
+	for _, test := range tests {
+		rule, err := parseIgnoreRule(test.entry)
+		assert.Nil(t, err)
+		assert.Equal(t, test.want, rule.matches(test.rel, test.line), "%s against %s:%d", test.entry, test.rel, test.line)
+	}
+
+	for _, entry := range []string{"foo.go:", "foo.go:0", "foo.go:x", "[.go"} {
+		_, err := parseIgnoreRule(entry)
+		assert.NotNil(t, err, entry)
+	}
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	rules, err := readIgnoreFile(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(rules))
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ignoreFileName), []byte("# comment\n\n  a.go:3  \nb/*.go\n"), 0o644))
+	rules, err = readIgnoreFile(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []ignoreRule{{pattern: "a.go", line: 3}, {pattern: "b/*.go"}}, rules)
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ignoreFileName), []byte("a.go\na.go:-1\n"), 0o644))
+	_, err = readIgnoreFile(dir)
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), ignoreFileName+":2: invalid line"))
+}
+
+func TestIgnoreFile(t *testing.T) {
+	results, err := parseDir("./testdata/src/ignorefile", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.True(t, strings.Contains(results[0], "ignorefile.go:23:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "sub/sub.go:22:9: comparing pointers to basic types: string and string"))
+}
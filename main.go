@@ -19,170 +19,1214 @@ Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
 package main
 
 import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
-	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/gcexportdata"
 	"golang.org/x/tools/go/packages"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"ptrcomp/analyzer"
+	"ptrcomp/report"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// driverConfig configures how a run loads packages and which findings it
+// keeps, beyond the analyzer's Options. The zero value analyzes every package
+// of the host platform, loading dependencies from source, and drops findings
+// in generated and vendored files.
+type driverConfig struct {
+	// overlay maps absolute file paths to contents that replace them on disk
+	// when loading packages, e.g. unsaved editor buffers.
+	overlay map[string][]byte
+
+	// checkGenerated reports findings in generated and vendored files like
+	// any other. Otherwise they are dropped, or with generatedInfo reported
+	// at info severity so they don't count as failures.
+	checkGenerated, generatedInfo bool
+
+	// skipErroredFiles drops every finding in a file with a parse or type
+	// error. Otherwise comparisons whose operands type-check are reported
+	// even if something else in the file doesn't.
+	skipErroredFiles bool
+
+	// failOnRules are the IDs of the rules whose findings are errors, set by
+	// -fail-on-rule. Findings of other rules are reported as warnings, which
+	// don't affect the exit status. If it is nil every rule's findings are
+	// errors.
+	failOnRules map[string]bool
+
+	// fastLoad skips loading dependencies from source, type-checking only
+	// the packages analyzed against the export data of their imports.
+	// Operands whose types can't be resolved that way are skipped, so it may
+	// under-report.
+	fastLoad bool
+
+	// platforms are the GOOS/GOARCH pairs, e.g. "linux/amd64", whose build
+	// constraints packages are loaded with in turn for -all-platforms. If it
+	// is empty only the host platform's are.
+	platforms []string
+
+	// changedDirs restricts analysis to the packages in these absolute
+	// directories, for -only-changed-packages, or is nil to analyze every
+	// package.
+	changedDirs []string
+
+	// progress reports how many packages have been analyzed while
+	// analyzeDir runs, or is nil when -progress is off.
+	progress *progressLine
+
+	// logger writes warnings about packages, and the panics of those that
+	// can't be analyzed, to run's stderr. If it is nil they go to the
+	// standard logger.
+	logger *log.Logger
+}
+
+// log returns c.logger, or the standard logger if it isn't set.
+func (c driverConfig) log() *log.Logger {
+	if c.logger == nil {
+		return log.Default()
+	}
+	return c.logger
+}
+
+// defaultPlatforms are the platforms -all-platforms analyzes unless -platforms
+// says otherwise.
+const defaultPlatforms = "darwin/arm64,linux/amd64,linux/arm64,windows/amd64"
+
+// newAnalyzer builds the analyzer run over every package. Tests replace it.
+var newAnalyzer = analyzer.NewPtrAnalyzerWithOptions
+
 func main() {
-	if len(os.Args) != 2 {
-		log.Fatal("Usage: ptrcmp <directory>")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with args, excluding the program name, and returns its
+// exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	logger := log.New(stderr, "", log.LstdFlags)
+	fs := flag.NewFlagSet("ptrcmp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var opts analyzer.Options
+	opts.RegisterFlags(fs)
+	cfg := driverConfig{logger: logger}
+	printRules := fs.Bool("list-rules", false, "print the available rules and their default state, then exit")
+	initConfig := fs.Bool("init", false, "write "+configFileName+" with every setting at its default, commented out, to the working directory, then exit")
+	force := fs.Bool("force", false, "let -init overwrite an existing "+configFileName)
+	printSchema := fs.Bool("config-schema", false, "print a JSON description of every flag, with its type, default and allowed values, then exit")
+	colorMode := fs.String("color", "auto", "colorize output: auto, always or never")
+	format := fs.String("format", "text", "output format: text, json, sarif, junit or template")
+	templateFile := fs.String("template-file", "", "text/template file executed for each finding with -format=template, e.g. {{.File}}:{{.Line}}: {{.RuleID}}")
+	contextLines := fs.Int("context", 0, "print this many lines of source before and after each finding, text format only")
+	overlayFile := fs.String("overlay", "", "JSON file in go build -overlay format replacing file contents when loading packages")
+	compact := fs.Bool("compact", false, "print one summary line per file instead of every finding")
+	stream := fs.Bool("stream", false, "print findings as each package is analyzed instead of sorting them across packages")
+	printStats := fs.Bool("stats", false, "write a JSON summary of the run to stderr")
+	typeSummary := fs.Bool("type-summary", false, "write how often each combination of operand types and operator was reported to stderr, e.g. \"*int == *int: 42 times\"")
+	metricsFile := fs.String("metrics-file", "", "write Prometheus metrics of the run to this file, for node_exporter's textfile collector")
+	quietClean := fs.Bool("quiet-clean", false, "print nothing at all, including -stats, when there are no findings")
+	collapse := fs.Bool("collapse-duplicates", false, "report only the first of identical comparisons in a file, noting how many more there are")
+	perLine := fs.Bool("once-per-line", false, "report only the first finding on each line, noting how many more there are")
+	failFast := fs.Bool("fail-fast", false, "stop at the first finding, print only it and exit with status 1")
+	diff := fs.Bool("diff", false, "print a unified diff of the suggested fixes instead of the findings, without changing any files")
+	expectFile := fs.String("expect", "", "JSON file of the expected findings, as written by -format=json; print the differences and fail if they don't match exactly")
+	limit := fs.Int("limit", 0, "print at most this many findings, noting how many more were suppressed (default unlimited)")
+	timeout := fs.Duration("timeout", 0, "give up loading and analyzing packages after this long, e.g. 5m (default no timeout)")
+	fs.BoolVar(&cfg.checkGenerated, "check-generated", false, "report findings in generated and vendored files like any other")
+	allPlatforms := fs.Bool("all-platforms", false, "analyze the files of every platform in -platforms instead of only the host's, reporting each finding once")
+	platformList := fs.String("platforms", defaultPlatforms, "comma separated GOOS/GOARCH pairs analyzed by -all-platforms")
+	fs.BoolVar(&cfg.fastLoad, "fast", false, "don't load dependencies from source, which is faster on large repositories but may miss findings")
+	fs.BoolVar(&cfg.skipErroredFiles, "skip-errored-files", false, "report nothing in files with parse or type errors")
+	failOnRule := fs.String("fail-on-rule", "", "comma separated rule IDs whose findings fail the run, e.g. cross-type,nil; findings of other rules are warnings (default every rule)")
+	fs.BoolVar(&cfg.generatedInfo, "generated-info", false, "report findings in generated and vendored files at info severity instead of dropping them")
+	onlyChanged := fs.Bool("only-changed-packages", false, "only analyze the packages containing changed files, from -changed-files or git diff -since")
+	since := fs.String("since", "HEAD", "git revision to find changed files against for -only-changed-packages")
+	changedFiles := fs.String("changed-files", "", "comma separated changed files for -only-changed-packages, instead of asking git")
+	debug := fs.Bool("debug", false, "log every comparison visited and why it was or wasn't reported to stderr")
+	pathStyle := fs.String("path-style", "native", "separator in printed file paths: native for the operating system's, or posix for forward slashes everywhere")
+	asciiOnly := fs.Bool("ascii-only", false, "escape non-ASCII characters in messages, e.g. in type names, as \\u sequences for consoles that aren't UTF-8")
+	relTo := fs.String("rel-to", "", "print file paths relative to the working directory (cwd) or to the module containing them (module) instead of absolute")
+	showFunction := fs.Bool("function", false, "append the function containing each finding to text output, e.g. \" (in (*Cache).Get)\"")
+	strictExit := fs.Bool("strict-exit", false, "exit with 1 when there are findings, 2 on usage errors and 3 when packages can't be loaded or analyzed")
+	showProgress := fs.Bool("progress", false, "write how many packages have been analyzed to stderr while running, if it is a terminal")
+	if err := fs.Parse(args); err != nil {
+		// Flags before the bad one are set, so a leading -strict-exit counts.
+		return usageStatus(*strictExit)
+	}
+	if *force && !*initConfig {
+		logger.Print("-force requires -init")
+		return usageStatus(*strictExit)
+	}
+	if *initConfig {
+		if err := writeConfigFile(fs, *force); err != nil {
+			logger.Printf("Error %v", err)
+			return errorStatus(*strictExit)
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", configFileName)
+		return exitClean
+	}
+	if err := applyConfigFile(fs); err != nil {
+		logger.Printf("Error %v", err)
+		return usageStatus(*strictExit)
+	}
+	if *debug {
+		opts.Logger = slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+	}
+	if *printSchema {
+		if err := writeConfigSchema(stdout, fs); err != nil {
+			logger.Printf("Error %v", err)
+			return errorStatus(*strictExit)
+		}
+		return exitClean
+	}
+	if *printRules {
+		listRules(stdout)
+		return exitClean
+	}
+	if fs.NArg() != 1 {
+		logger.Print("Usage: ptrcmp [flags] <directory>")
+		return usageStatus(*strictExit)
+	}
+	dir := fs.Arg(0)
+	color, err := colorEnabled(*colorMode, stdout)
+	if err != nil {
+		logger.Print(err)
+		return usageStatus(*strictExit)
+	}
+	if (*format == "template") != (*templateFile != "") {
+		logger.Print("-format=template requires -template-file, and -template-file requires -format=template")
+		return usageStatus(*strictExit)
+	}
+	// With -quiet-clean the reporter's output is dropped until there are
+	// findings, so it can still be flushed when there are none.
+	out := &quietWriter{w: stdout, quiet: *quietClean}
+	var reporter report.Reporter
+	if *format == "template" {
+		reporter, err = newTemplateReporter(*templateFile, out)
+	} else {
+		reporter, err = newReporter(*format, out, color)
+	}
+	if err != nil {
+		logger.Print(err)
+		return usageStatus(*strictExit)
+	}
+	if *stream && *compact {
+		logger.Print("-stream cannot be combined with -compact")
+		return usageStatus(*strictExit)
+	}
+	if *format != "text" && (*compact || *diff) {
+		logger.Print("-format cannot be combined with -compact or -diff")
+		return usageStatus(*strictExit)
+	}
+	var expected []report.Finding
+	if *expectFile != "" {
+		if *format != "text" || *compact || *diff || *stream || *contextLines > 0 {
+			logger.Print("-expect cannot be combined with -format, -compact, -diff, -stream or -context")
+			return usageStatus(*strictExit)
+		}
+		expected, err = readExpected(*expectFile)
+		if err != nil {
+			logger.Printf("Error %v", err)
+			return usageStatus(*strictExit)
+		}
+	}
+	if text, ok := reporter.(*report.Text); ok {
+		text.Function = *showFunction
+	}
+	if *contextLines > 0 {
+		text, ok := reporter.(*report.Text)
+		if !ok || *compact || *diff {
+			logger.Print("-context only applies to the default text output")
+			return usageStatus(*strictExit)
+		}
+		text.Context, text.ReadFile = *contextLines, cfg.readSource
+	}
+	if *pathStyle != "native" && *pathStyle != "posix" {
+		logger.Printf("invalid -path-style value %q: must be native or posix", *pathStyle)
+		return usageStatus(*strictExit)
+	}
+	if *relTo != "" {
+		if *relTo != "cwd" && *relTo != "module" {
+			logger.Printf("invalid -rel-to value %q: must be cwd or module", *relTo)
+			return usageStatus(*strictExit)
+		}
+		if *diff || *expectFile != "" || *contextLines > 0 {
+			logger.Print("-rel-to cannot be combined with -diff, -expect or -context")
+			return usageStatus(*strictExit)
+		}
+	}
+	if *diff && (*stream || *compact) {
+		logger.Print("-diff cannot be combined with -stream or -compact")
+		return usageStatus(*strictExit)
+	}
+	if *overlayFile != "" {
+		cfg.overlay, err = readOverlay(*overlayFile)
+		if err != nil {
+			logger.Printf("Error %v", err)
+			return usageStatus(*strictExit)
+		}
+	}
+
+	if !*onlyChanged && (*changedFiles != "" || *since != "HEAD") {
+		logger.Print("-since and -changed-files require -only-changed-packages")
+		return usageStatus(*strictExit)
+	}
+	if *failOnRule != "" {
+		cfg.failOnRules, err = parseFailOnRules(*failOnRule)
+		if err != nil {
+			logger.Print(err)
+			return usageStatus(*strictExit)
+		}
+	}
+	if *allPlatforms {
+		cfg.platforms, err = parsePlatforms(*platformList)
+		if err != nil {
+			logger.Print(err)
+			return usageStatus(*strictExit)
+		}
+	} else if *platformList != defaultPlatforms {
+		logger.Print("-platforms requires -all-platforms")
+		return usageStatus(*strictExit)
+	}
+	if cfg.fastLoad {
+		logger.Print("warning: -fast doesn't load dependencies from source, so findings may be missing")
+	}
+	if f, ok := stderr.(*os.File); ok && *showProgress && isTerminal(f) {
+		cfg.progress = newProgressLine(stderr)
+	}
+
+	start := time.Now()
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	if *onlyChanged {
+		cfg.changedDirs, err = onlyChangedDirs(ctx, dir, *since, *changedFiles)
+		if err != nil {
+			logger.Printf("can't tell which packages changed, analyzing every package: %v", err)
+		}
+	}
+
+	// printable applies -rel-to, -path-style and -ascii-only to findings
+	// about to be printed.
+	printable := func(findings []finding) []finding {
+		findings = relativePaths(findings, *relTo)
+		if *pathStyle == "posix" {
+			findings = posixPaths(findings)
+		}
+		return escapeFindings(findings, *asciiOnly)
+	}
+
+	// take applies -limit to findings about to be printed, counting the rest
+	// as suppressed. With -compact the limit applies to the summary lines
+	// instead, so the files shown are counted in full, and unit is "file".
+	shown, suppressed, unit := 0, 0, "finding"
+	take := func(findings []finding) []finding {
+		if *limit <= 0 {
+			return findings
+		}
+		n := min(len(findings), max(*limit-shown, 0))
+		shown += n
+		suppressed += len(findings) - n
+		return findings[:n]
+	}
+
+	var firstError *finding
+	var emit func([]finding) bool
+	if *stream || *failFast {
+		emit = func(pkgFindings []finding) bool {
+			if *failFast {
+				if i := slices.IndexFunc(pkgFindings, func(f finding) bool { return f.Severity == report.SeverityError }); i >= 0 {
+					firstError = &pkgFindings[i]
+					return false
+				}
+			}
+			if *stream {
+				if *collapse {
+					pkgFindings = collapseDuplicates(pkgFindings)
+				}
+				if *perLine {
+					pkgFindings = oncePerLine(pkgFindings)
+				}
+				if len(pkgFindings) > 0 {
+					out.quiet = false
+				}
+				writeResults(reporter, printable(take(pkgFindings)))
+			}
+			return true
+		}
 	}
-	dir := os.Args[1]
 
-	results, err := parseDir(dir)
+	findings, stats, err := analyzeDir(ctx, dir, opts, cfg, emit)
+	cfg.progress.clear()
+	if errors.Is(err, errNotInModule) {
+		logger.Print(err)
+		return exitUsage
+	}
+	var failed *failedPackagesError
+	if err != nil && !errors.As(err, &failed) {
+		logger.Printf("Error %v", err)
+		return errorStatus(*strictExit)
+	}
+	stats.finish(findings, time.Since(start))
+	if *metricsFile != "" {
+		if err := writeMetricsFile(*metricsFile, stats); err != nil {
+			logger.Printf("Error writing metrics: %v", err)
+			return errorStatus(*strictExit)
+		}
+	}
+	if len(findings) > 0 || firstError != nil {
+		out.quiet = false
+	}
+	// flush flushes the reporter, which every path below must do exactly
+	// once, even when -compact, -diff or -expect print without it.
+	flush := func() bool {
+		if err := reporter.Flush(); err != nil {
+			logger.Printf("Error %v", err)
+			return false
+		}
+		return true
+	}
+	if firstError != nil {
+		writeResults(reporter, printable([]finding{*firstError}))
+		flush()
+		return exitFindings
+	}
+	if *expectFile != "" {
+		matched := writeExpectationDiff(stdout, expected, findings)
+		if !flush() {
+			return errorStatus(*strictExit)
+		}
+		if !matched {
+			logger.Printf("findings don't match %s", *expectFile)
+			return exitFindings
+		}
+		return exitClean
+	}
+	if *quietClean && len(findings) == 0 {
+		if !flush() {
+			return errorStatus(*strictExit)
+		}
+		return exitClean
+	}
+	printed := findings
+	if *collapse {
+		printed = collapseDuplicates(printed)
+	}
+	if *perLine {
+		printed = oncePerLine(printed)
+	}
+	switch {
+	case *diff:
+		if err := writeDiff(stdout, findings, cfg.readSource); err != nil {
+			logger.Printf("Error %v", err)
+			flush()
+			return errorStatus(*strictExit)
+		}
+	case *compact:
+		suppressed, unit = writeCompact(stdout, printable(printed), color, *limit), "file"
+	case !*stream:
+		writeResults(reporter, printable(take(printed)))
+	}
+	if !flush() {
+		return errorStatus(*strictExit)
+	}
+	if suppressed > 0 {
+		noun := unit + "s"
+		if suppressed == 1 {
+			noun = unit
+		}
+		fmt.Fprintf(stderr, "%d more %s not shown (see -limit)\n", suppressed, noun)
+	}
+	if *typeSummary {
+		writeTypeSummary(stderr, findings)
+	}
+	if *printStats {
+		if err := writeStats(stderr, stats); err != nil {
+			logger.Printf("Error %v", err)
+			return errorStatus(*strictExit)
+		}
+	}
+	if failed != nil {
+		logger.Printf("Error %v", failed)
+		return errorStatus(*strictExit)
+	}
+	if (*strictExit || cfg.failOnRules != nil) && slices.ContainsFunc(findings, func(f finding) bool { return f.Severity == report.SeverityError }) {
+		return exitFindings
+	}
+	return exitClean
+}
+
+// Exit statuses. Without -strict-exit, findings exit with exitClean and most
+// usage and analysis errors with exitFindings. Directories outside a module
+// exit with exitUsage, and -fail-fast and -expect with exitFindings when they
+// fail, either way.
+const (
+	exitClean    = 0
+	exitFindings = 1
+	exitUsage    = 2
+	exitError    = 3
+)
+
+// usageStatus returns the exit status for invalid flags or arguments.
+func usageStatus(strict bool) int {
+	if strict {
+		return exitUsage
+	}
+	return exitFindings
+}
+
+// errorStatus returns the exit status for packages that can't be loaded or
+// analyzed, and for output that can't be written.
+func errorStatus(strict bool) int {
+	if strict {
+		return exitError
+	}
+	return exitFindings
+}
+
+// File categories a finding can be in.
+const (
+	firstParty = "first-party"
+	generated  = "generated"
+	vendored   = "vendored"
+)
+
+// finding is a single reported comparison, with what the driver needs beyond
+// what is reported.
+type finding struct {
+	report.Finding
+	// Expr is the reported comparison as printed by types.ExprString, or
+	// empty if it couldn't be found.
+	Expr string
+	// Fix holds the edits of the first suggested fix, if any.
+	Fix []textEdit
+	// ModuleDir is the root directory of the module containing the finding,
+	// or empty outside of modules.
+	ModuleDir string
+}
+
+func parseDir(dir string, opts analyzer.Options) ([]string, error) {
+	findings, _, err := analyzeDir(context.Background(), dir, opts, driverConfig{}, nil)
 	if err != nil {
-		log.Fatalf("Error %v", err)
+		return []string{}, err
+	}
+
+	results := make([]string, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, f.String())
+	}
+	return results, nil
+}
+
+// analyzeDir returns the findings in every package under dir, sorted by
+// position, and statistics about the run. If emit is non-nil it is also
+// called with each package's findings as soon as the package has been analyzed,
+// and no further packages are analyzed once it returns false.
+// Findings suppressed by the .ptrcmpignore file in dir are left out.
+// A package whose analysis panics is logged and skipped, and the findings in
+// the other packages are returned along with a *failedPackagesError.
+//
+// With -all-platforms the packages are loaded and analyzed once per platform,
+// and a finding in a file built on several platforms is only returned, and
+// emitted, once. Packages and files are counted once per platform.
+func analyzeDir(ctx context.Context, dir string, opts analyzer.Options, cfg driverConfig, emit func([]finding) bool) ([]finding, runStats, error) {
+	if len(cfg.platforms) == 0 {
+		return analyzePlatform(ctx, dir, nil, opts, cfg, emit)
+	}
+	stats := newRunStats()
+	var findings []finding
+	var failed []string
+	emitted := make(map[findingKey]bool)
+	stopped := false
+	for _, platform := range cfg.platforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		platformEmit := emit
+		if emit != nil {
+			platformEmit = func(pkgFindings []finding) bool {
+				pkgFindings = slices.DeleteFunc(pkgFindings, func(f finding) bool {
+					key := findingKey{f.Pos, f.Message}
+					if emitted[key] {
+						return true
+					}
+					emitted[key] = true
+					return false
+				})
+				if len(pkgFindings) == 0 {
+					return true
+				}
+				stopped = !emit(pkgFindings)
+				return !stopped
+			}
+		}
+		debugLog(opts, "analyzing platform", "platform", platform)
+		platformFindings, platformStats, err := analyzePlatform(ctx, dir, []string{"GOOS=" + goos, "GOARCH=" + goarch}, opts, cfg, platformEmit)
+		var platformFailed *failedPackagesError
+		if errors.As(err, &platformFailed) {
+			for _, pkg := range platformFailed.Packages {
+				failed = append(failed, pkg+" ("+platform+")")
+			}
+		} else if err != nil {
+			return nil, stats, fmt.Errorf("%s: %w", platform, err)
+		}
+		stats.Packages += platformStats.Packages
+		stats.Files += platformStats.Files
+		findings = append(findings, platformFindings...)
+		if stopped {
+			break
+		}
 	}
-	for _, result := range results {
-		println(result)
+	if len(failed) > 0 {
+		return sortFindings(findings), stats, &failedPackagesError{Packages: failed}
 	}
+	return sortFindings(findings), stats, nil
 }
 
-func parseDir(dir string) ([]string, error) {
-	cfg := &packages.Config{
-		Mode: packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps,
-		Dir:  dir,
+// findingKey identifies findings that sortFindings considers duplicates.
+type findingKey struct {
+	pos     token.Position
+	message string
+}
+
+// parseFailOnRules parses the comma separated rule IDs given to -fail-on-rule.
+func parseFailOnRules(list string) (map[string]bool, error) {
+	rules := make(map[string]bool)
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if !slices.ContainsFunc(analyzer.Rules, func(r analyzer.Rule) bool { return r.ID == id }) {
+			return nil, fmt.Errorf("invalid -fail-on-rule entry %q: not a rule, see -list-rules", id)
+		}
+		rules[id] = true
+	}
+	return rules, nil
+}
+
+// parsePlatforms splits a comma separated list of GOOS/GOARCH pairs, as given
+// to -platforms.
+func parsePlatforms(list string) ([]string, error) {
+	var pairs []string
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		goos, goarch, ok := strings.Cut(pair, "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return nil, fmt.Errorf("invalid -platforms entry %q: must be GOOS/GOARCH, e.g. linux/amd64", pair)
+		}
+		if !slices.Contains(pairs, pair) {
+			pairs = append(pairs, pair)
+		}
 	}
+	return pairs, nil
+}
 
-	pkgs, err := packages.Load(cfg, "./...")
+// analyzePlatform is analyzeDir for a single platform, loading packages with
+// the GOOS and GOARCH variables in platformEnv, or the host's if it is nil.
+func analyzePlatform(ctx context.Context, dir string, platformEnv []string, opts analyzer.Options, cfg driverConfig, emit func([]finding) bool) ([]finding, runStats, error) {
+	stats := newRunStats()
+	pkgs, err := loadPackages(ctx, dir, opts.CheckTests, platformEnv, cfg)
 	if err != nil {
-		return []string{}, fmt.Errorf("failed to load packages: %v", err)
+		return nil, stats, err
 	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, stats, err
+	}
+	ignoreRules, err := readIgnoreFile(root)
+	if err != nil {
+		return nil, stats, err
+	}
+	ptrAnalyzer := newAnalyzer(opts)
 
-	var errs []error
-	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
-		for _, err := range pkg.Errors {
-			errs = append(errs, err)
+	findings := make([]finding, 0)
+	var failed []string
+	for i, pkg := range pkgs {
+		if err := ctx.Err(); err != nil {
+			return nil, stats, fmt.Errorf("analysis stopped: %v", timeoutError(err))
 		}
-	})
-	if len(errs) > 0 {
-		log.Println("Packages contain errors:")
-		for _, err := range errs {
-			log.Println(err)
-		}
-	}
-	ptrAnalyzer := NewPtrAnalyzer()
-
-	results := make([]string, 0)
-	for _, pkg := range pkgs {
-		pass := &analysis.Pass{
-			Analyzer:   ptrAnalyzer,
-			Fset:       pkg.Fset,
-			Files:      pkg.Syntax,
-			OtherFiles: nil,
-			Pkg:        pkg.Types,
-			TypesInfo:  pkg.TypesInfo,
-			TypesSizes: pkg.TypesSizes,
-			ResultOf:   make(map[*analysis.Analyzer]interface{}),
-			Report: func(d analysis.Diagnostic) {
-				pos := pkg.Fset.Position(d.Pos)
-				results = append(results, fmt.Sprintf("%s:%d:%d: %s", pos.Filename, pos.Line, pos.Column, d.Message))
-			},
+		cfg.progress.update(i, len(pkgs))
+		debugLog(opts, "analyzing package", "package", pkg.ID, "files", len(pkg.Syntax))
+		stats.Packages++
+		stats.Files += len(pkg.Syntax)
+		categories := make(map[string]string, len(pkg.Syntax))
+		files := make(map[string]*ast.File, len(pkg.Syntax))
+		for _, file := range pkg.Syntax {
+			name := pkg.Fset.File(file.Pos()).Name()
+			categories[name] = fileCategory(name, file)
+			files[name] = file
 		}
 
-		inspectPass := &analysis.Pass{
-			Analyzer:   inspect.Analyzer,
-			Fset:       pkg.Fset,
-			Files:      pkg.Syntax,
-			OtherFiles: nil,
-			Pkg:        pkg.Types,
-			TypesInfo:  pkg.TypesInfo,
-			TypesSizes: pkg.TypesSizes,
-			ResultOf:   make(map[*analysis.Analyzer]interface{}),
-			Report:     func(d analysis.Diagnostic) {},
+		var errored map[string]bool
+		if cfg.skipErroredFiles {
+			errored = erroredFiles(pkg)
 		}
-		result, err := inspect.Analyzer.Run(inspectPass)
-		if err != nil {
-			log.Printf("Failed to run inspect analyzer on package %s: %v\n", pkg.Name, err)
+
+		var pkgFindings []finding
+		err := analyzePackage(ptrAnalyzer, pkg, cfg.readSource, func(d analysis.Diagnostic) {
+			pos := pkg.Fset.Position(d.Pos)
+			if errored[pos.Filename] {
+				debugLog(opts, "finding dropped, file has errors", "pos", pos.String())
+				return
+			}
+			if ignored(ignoreRules, root, pos) {
+				debugLog(opts, "finding dropped by "+ignoreFileName, "pos", pos.String())
+				return
+			}
+			f := finding{Finding: report.Finding{Pos: pos, Message: d.Message, Rule: d.Category, Category: cmp.Or(categories[pos.Filename], firstParty), Severity: report.SeverityError}}
+			for _, r := range d.Related {
+				f.Related = append(f.Related, report.Location{Pos: pkg.Fset.Position(r.Pos), Message: r.Message})
+			}
+			if pkg.Module != nil {
+				f.ModuleDir = pkg.Module.Dir
+			}
+			if file := files[pos.Filename]; file != nil {
+				var expr ast.Expr
+				if d.End.IsValid() {
+					expr = reportedExpr(file, d.Pos, d.End)
+				}
+				if expr != nil {
+					f.Expr = types.ExprString(expr)
+				}
+				if comparison, ok := expr.(*ast.BinaryExpr); ok {
+					f.Op = comparison.Op.String()
+					f.Left = types.TypeString(pkg.TypesInfo.TypeOf(comparison.X), nil)
+					f.Right = types.TypeString(pkg.TypesInfo.TypeOf(comparison.Y), nil)
+				}
+				f.Function = enclosingFunction(file, d.Pos)
+			}
+			if len(d.SuggestedFixes) > 0 {
+				for _, e := range d.SuggestedFixes[0].TextEdits {
+					start, end := pkg.Fset.Position(e.Pos).Offset, pkg.Fset.Position(cmp.Or(e.End, e.Pos)).Offset
+					f.Fix = append(f.Fix, textEdit{Start: start, End: end, NewText: string(e.NewText)})
+				}
+			}
+			if f.Category != firstParty && !cfg.checkGenerated {
+				if !cfg.generatedInfo {
+					debugLog(opts, "finding dropped in "+f.Category+" file, see -check-generated", "pos", pos.String())
+					return
+				}
+				f.Severity = report.SeverityInfo
+			}
+			if cfg.failOnRules != nil && !cfg.failOnRules[f.Rule] && f.Severity == report.SeverityError {
+				f.Severity = report.SeverityWarning
+			}
+			pkgFindings = append(pkgFindings, f)
+		})
+		var panicked *panicError
+		if errors.As(err, &panicked) {
+			cfg.progress.clear()
+			cfg.log().Println(panicked)
+			failed = append(failed, pkg.ID)
 			continue
 		}
-		pass.ResultOf[inspect.Analyzer] = result
-		_, err = ptrAnalyzer.Run(pass)
 		if err != nil {
-			return []string{}, fmt.Errorf("Failed to run analyzer on package %s: %v\n", pkg.Name, err)
+			return nil, stats, err
 		}
+		findings = append(findings, pkgFindings...)
+		if emit != nil && len(pkgFindings) > 0 {
+			// Streamed findings may go to the same terminal.
+			cfg.progress.clear()
+			if !emit(sortFindings(slices.Clone(pkgFindings))) {
+				break
+			}
+		}
+		cfg.progress.update(i+1, len(pkgs))
 	}
-	return results, nil
+	if len(failed) > 0 {
+		return sortFindings(findings), stats, &failedPackagesError{Packages: failed}
+	}
+	return sortFindings(findings), stats, nil
 }
 
-func NewPtrAnalyzer() *analysis.Analyzer {
-	return &analysis.Analyzer{
-		Name:     "ptrcmp",
-		Doc:      "checks that there are no pointer comparisons between basic types",
-		Requires: []*analysis.Analyzer{inspect.Analyzer},
-		Run:      run,
+// debugLog logs msg to opts.Logger at debug level, if it is set.
+func debugLog(opts analyzer.Options, msg string, args ...any) {
+	if opts.Logger != nil {
+		opts.Logger.Debug(msg, args...)
 	}
 }
 
-func run(pass *analysis.Pass) (any, error) {
-	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+// failedPackagesError lists the packages skipped because their analysis
+// panicked.
+type failedPackagesError struct {
+	Packages []string
+}
 
-	nodeFilter := []ast.Node{
-		(*ast.BinaryExpr)(nil),
-	}
+func (e *failedPackagesError) Error() string {
+	return fmt.Sprintf("analysis failed for %d package(s): %s", len(e.Packages), strings.Join(e.Packages, ", "))
+}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
-		if n == nil {
-			return
-		}
+// panicError is a panic recovered while analyzing a single package.
+type panicError struct {
+	Package string
+	Value   any
+	Stack   []byte
+}
 
-		if binaryExpr, ok := n.(*ast.BinaryExpr); ok {
-			switch binaryExpr.Op {
-			case token.EQL, token.NEQ, token.LSS, token.GTR, token.LEQ, token.GEQ:
-				if isPointerType(pass, binaryExpr.X) && isPointerType(pass, binaryExpr.Y) {
-					leftType := getUnderlyingType(pass, binaryExpr.X)
-					rightType := getUnderlyingType(pass, binaryExpr.Y)
-					if isBasicType(leftType) && isBasicType(rightType) {
-						pass.Report(
-							analysis.Diagnostic{
-								Pos:     binaryExpr.Pos(),
-								Message: fmt.Sprintf("comparing pointers to basic types: %v and %v", leftType, rightType),
-							},
-						)
-					}
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic analyzing package %s: %v\n%s", e.Package, e.Value, e.Stack)
+}
+
+// erroredFiles returns the files of pkg with parse or type errors.
+func erroredFiles(pkg *packages.Package) map[string]bool {
+	files := make(map[string]bool)
+	for _, err := range pkg.Errors {
+		// Pos is "file:line:col", "file:line", "file" or empty.
+		name := err.Pos
+		for range 2 {
+			if i := strings.LastIndex(name, ":"); i > 0 {
+				if _, convErr := strconv.Atoi(name[i+1:]); convErr == nil {
+					name = name[:i]
 				}
-			default:
 			}
 		}
+		if name != "" && name != "-" {
+			files[name] = true
+		}
+	}
+	return files
+}
+
+// reportedExpr returns the expression spanning pos to end in file, or nil if
+// there is none.
+func reportedExpr(file *ast.File, pos, end token.Pos) ast.Expr {
+	path, exact := astutil.PathEnclosingInterval(file, pos, end)
+	if !exact || len(path) == 0 {
+		return nil
+	}
+	expr, _ := path[0].(ast.Expr)
+	return expr
+}
+
+// enclosingFunction returns the name of the function in file containing pos,
+// named like in runtime stack traces: "Load", "(*Cache).Get" for methods and
+// "Load.func1" or "Load.func1.2" for closures, numbered in source order. It
+// returns "" outside of function declarations.
+func enclosingFunction(file *ast.File, pos token.Pos) string {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	var name string
+	var scope ast.Node
+	for i := len(path) - 1; i >= 0; i-- {
+		switch n := path[i].(type) {
+		case *ast.FuncDecl:
+			if n.Body == nil {
+				return ""
+			}
+			name, scope = n.Name.Name, n.Body
+			if n.Recv != nil && len(n.Recv.List) == 1 {
+				name = receiverName(n.Recv.List[0].Type) + "." + name
+			}
+		case *ast.FuncLit:
+			if scope == nil {
+				continue
+			}
+			sep := "."
+			if _, ok := scope.(*ast.BlockStmt); ok {
+				sep = ".func"
+			}
+			name += sep + strconv.Itoa(closureIndex(scope, n))
+			scope = n
+		}
+	}
+	return name
+}
+
+// receiverName formats a receiver type like runtime stack traces, e.g. "T",
+// "(*T)" or "(*T[...])" for generic types.
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return receiverName(expr.X)
+	case *ast.StarExpr:
+		return "(*" + receiverName(expr.X) + ")"
+	case *ast.IndexExpr:
+		return receiverName(expr.X) + "[...]"
+	case *ast.IndexListExpr:
+		return receiverName(expr.X) + "[...]"
+	}
+	return types.ExprString(expr)
+}
+
+// closureIndex returns the 1-based position of lit among the function
+// literals directly inside scope, excluding those nested in other literals.
+func closureIndex(scope ast.Node, lit *ast.FuncLit) int {
+	index, found := 0, false
+	ast.Inspect(scope, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if l, ok := n.(*ast.FuncLit); ok && n != scope {
+			index++
+			found = l == lit
+			return false
+		}
+		return true
 	})
-	return nil, nil
+	return index
 }
 
-func isPointerType(pass *analysis.Pass, expr ast.Expr) bool {
-	exprType := pass.TypesInfo.TypeOf(expr)
-	if exprType == nil {
-		return false
+// collapseDuplicates keeps only the first of the sorted findings reporting the
+// same comparison in the same file, noting how many more there were.
+func collapseDuplicates(findings []finding) []finding {
+	type key struct{ file, expr string }
+	first := make(map[key]int)
+	repeats := make(map[int]int)
+	collapsed := make([]finding, 0, len(findings))
+	for _, f := range findings {
+		if f.Expr == "" {
+			collapsed = append(collapsed, f)
+			continue
+		}
+		k := key{f.Pos.Filename, f.Expr}
+		if i, ok := first[k]; ok {
+			repeats[i]++
+			continue
+		}
+		first[k] = len(collapsed)
+		collapsed = append(collapsed, f)
+	}
+	for i, n := range repeats {
+		times := "times"
+		if n == 1 {
+			times = "time"
+		}
+		collapsed[i].Message += fmt.Sprintf(" (repeated %d more %s in this file)", n, times)
+	}
+	return collapsed
+}
+
+// oncePerLine keeps only the first of the sorted findings on each line of a
+// file, noting how many more there were, e.g. for a == b && c == d.
+func oncePerLine(findings []finding) []finding {
+	kept := make([]finding, 0, len(findings))
+	more := 0
+	for _, f := range findings {
+		if n := len(kept); n > 0 && kept[n-1].Pos.Filename == f.Pos.Filename && kept[n-1].Pos.Line == f.Pos.Line {
+			more++
+			continue
+		}
+		noteMoreOnLine(kept, more)
+		kept = append(kept, f)
+		more = 0
 	}
+	noteMoreOnLine(kept, more)
+	return kept
+}
 
-	_, isPtr := exprType.(*types.Pointer)
-	return isPtr
+// noteMoreOnLine appends the number of findings oncePerLine dropped after the
+// last of kept to its message.
+func noteMoreOnLine(kept []finding, more int) {
+	if more == 0 {
+		return
+	}
+	noun := "findings"
+	if more == 1 {
+		noun = "finding"
+	}
+	kept[len(kept)-1].Message += fmt.Sprintf(" (%d more %s on this line)", more, noun)
 }
 
-func getUnderlyingType(pass *analysis.Pass, expr ast.Expr) types.Type {
-	exprType := pass.TypesInfo.TypeOf(expr)
-	if exprType == nil {
-		return nil
+// fileCategory classifies a file as vendored, generated or first-party.
+func fileCategory(filename string, file *ast.File) string {
+	sep := string(filepath.Separator)
+	switch {
+	case strings.Contains(filename, sep+"vendor"+sep):
+		return vendored
+	case ast.IsGenerated(file):
+		return generated
+	default:
+		return firstParty
+	}
+}
+
+// sortFindings orders findings by position and drops duplicates, e.g. the same
+// file reported through more than one package.
+func sortFindings(findings []finding) []finding {
+	slices.SortFunc(findings, func(a, b finding) int {
+		return cmp.Or(
+			cmp.Compare(a.Pos.Filename, b.Pos.Filename),
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Column, b.Pos.Column),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return slices.CompactFunc(findings, func(a, b finding) bool {
+		return a.Pos == b.Pos && a.Message == b.Message
+	})
+}
+
+func loadPackages(ctx context.Context, dir string, tests bool, platformEnv []string, cfg driverConfig) ([]*packages.Package, error) {
+	config := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps | packages.NeedModule,
+		Dir:     dir,
+		Tests:   tests,
+		Overlay: cfg.overlay,
+	}
+	if cfg.fastLoad {
+		if exportDataReadable(ctx, dir) {
+			config.Mode = config.Mode&^packages.NeedDeps | packages.NeedImports
+		} else {
+			cfg.log().Print("-fast: can't read the export data of this go toolchain, loading dependencies from source")
+		}
+	}
+
+	patterns, env, err := workspacePatterns(ctx, dir)
+	if errors.Is(err, errNotInModule) {
+		return nil, fmt.Errorf("%s is %w", dir, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %v", err)
+	}
+	config.Env = env
+	if platformEnv != nil {
+		if env == nil {
+			env = os.Environ()
+		}
+		config.Env = append(slices.Clip(env), platformEnv...)
+	}
+	if cfg.changedDirs != nil {
+		if len(cfg.changedDirs) == 0 {
+			return nil, nil
+		}
+		if patterns, err = changedPatterns(dir, cfg.changedDirs); err != nil {
+			return nil, fmt.Errorf("failed to load packages: %v", err)
+		}
 	}
 
-	if ptr, ok := exprType.(*types.Pointer); ok {
-		return ptr.Elem()
+	pkgs, err := packages.Load(config, patterns...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("failed to load packages: %v", timeoutError(ctxErr))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %v", err)
 	}
 
-	return exprType
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) > 0 {
+		cfg.log().Println("Packages contain errors:")
+		for _, err := range errs {
+			cfg.log().Println(err)
+		}
+	}
+	return pkgs, nil
 }
 
-func isBasicType(t types.Type) bool {
-	if t == nil {
+// workspacePatterns returns the package patterns to load for dir and the
+// environment to load them in. Outside a go.work workspace that is just
+// "./...", but a workspace root usually isn't inside any module, so there
+// each module under dir is listed explicitly. GOFLAGS=-mod=mod is an error in
+// workspace mode, so it is dropped there rather than failing the whole run.
+// If dir is outside of any module in module mode it fails with errNotInModule.
+func workspacePatterns(ctx context.Context, dir string) ([]string, []string, error) {
+	patterns := []string{"./..."}
+	out, err := goCommand(ctx, dir, nil, "env", "GOMOD", "GOWORK")
+	if err != nil {
+		return patterns, nil, nil
+	}
+	gomod, gowork, _ := strings.Cut(out, "\n")
+	if gowork == "" || gowork == "off" {
+		// GOMOD is empty in GOPATH mode, and os.DevNull in module mode
+		// outside of any module.
+		if gomod == os.DevNull {
+			return nil, nil, errNotInModule
+		}
+		return patterns, nil, nil
+	}
+
+	env := os.Environ()
+	if flags := os.Getenv("GOFLAGS"); flags != "" {
+		kept := slices.DeleteFunc(strings.Fields(flags), func(f string) bool {
+			return strings.TrimLeft(f, "-") == "mod=mod"
+		})
+		env = append(env, "GOFLAGS="+strings.Join(kept, " "))
+	}
+	out, err = goCommand(ctx, dir, env, "list", "-m", "-f", "{{.Dir}}")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list workspace modules: %v", err)
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var modules []string
+	for _, modDir := range strings.Split(out, "\n") {
+		rel, err := filepath.Rel(root, modDir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		modules = append(modules, "./"+filepath.ToSlash(filepath.Join(rel, "...")))
+	}
+	if len(modules) == 0 {
+		return patterns, env, nil
+	}
+	return modules, env, nil
+}
+
+// exportDataReadable reports whether the export data the go command in dir
+// produces can be read, which -fast type-checks against instead of loading
+// dependencies from source. Export data from a go toolchain newer than
+// golang.org/x/tools can't be, and loading packages would then abort.
+func exportDataReadable(ctx context.Context, dir string) bool {
+	const probe = "errors"
+	name, err := goCommand(ctx, dir, nil, "list", "-export", "-f", "{{.Export}}", probe)
+	if err != nil || name == "" {
 		return false
 	}
-	_, isBasic := t.Underlying().(*types.Basic)
-	return isBasic
+	f, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	r, err := gcexportdata.NewReader(f)
+	if err != nil {
+		return false
+	}
+	_, err = gcexportdata.Read(r, token.NewFileSet(), make(map[string]*types.Package), probe)
+	return err == nil
+}
+
+// errNotInModule is returned when the directory to analyze is neither inside
+// a module nor a GOPATH package.
+var errNotInModule = errors.New("not inside a Go module; run from a module root or set GO111MODULE")
+
+// goCommand runs the go command in dir with env, or the current environment if
+// env is nil, and returns its trimmed standard output.
+func goCommand(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// timeoutError explains a context error caused by -timeout.
+func timeoutError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out (see -timeout): %v", err)
+	}
+	return err
+}
+
+// readSource returns the contents of the named file, as replaced by -overlay.
+func (c driverConfig) readSource(name string) ([]byte, error) {
+	if src, ok := c.overlay[name]; ok {
+		return src, nil
+	}
+	return os.ReadFile(name)
+}
+
+// readOverlay reads an overlay file in the format accepted by go build -overlay
+// and returns the replacement contents keyed by absolute path.
+func readOverlay(path string) (map[string][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay: %v", err)
+	}
+	var spec struct {
+		Replace map[string]string
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay %s: %v", path, err)
+	}
+
+	contents := make(map[string][]byte, len(spec.Replace))
+	for from, to := range spec.Replace {
+		if to == "" {
+			return nil, fmt.Errorf("overlay %s deletes %s, which is not supported", path, from)
+		}
+		abs, err := filepath.Abs(from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve overlay path %s: %v", from, err)
+		}
+		contents[abs], err = os.ReadFile(to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay replacement: %v", err)
+		}
+	}
+	return contents, nil
+}
+
+// analyzePackage runs ptrAnalyzer, and the inspect analyzer it requires, over
+// a single loaded package, passing every diagnostic to report and reading
+// source files with readFile. A panic in either analyzer is returned as a
+// *panicError.
+func analyzePackage(ptrAnalyzer *analysis.Analyzer, pkg *packages.Package, readFile func(string) ([]byte, error), report func(analysis.Diagnostic)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{Package: pkg.ID, Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	pass := &analysis.Pass{
+		Analyzer:   ptrAnalyzer,
+		Fset:       pkg.Fset,
+		Files:      pkg.Syntax,
+		OtherFiles: nil,
+		Pkg:        pkg.Types,
+		TypesInfo:  pkg.TypesInfo,
+		TypesSizes: pkg.TypesSizes,
+		ResultOf:   make(map[*analysis.Analyzer]interface{}),
+		Report:     report,
+		ReadFile:   readFile,
+	}
+
+	inspectPass := &analysis.Pass{
+		Analyzer:   inspect.Analyzer,
+		Fset:       pkg.Fset,
+		Files:      pkg.Syntax,
+		OtherFiles: nil,
+		Pkg:        pkg.Types,
+		TypesInfo:  pkg.TypesInfo,
+		TypesSizes: pkg.TypesSizes,
+		ResultOf:   make(map[*analysis.Analyzer]interface{}),
+		Report:     func(d analysis.Diagnostic) {},
+	}
+	result, err := inspect.Analyzer.Run(inspectPass)
+	if err != nil {
+		log.Printf("Failed to run inspect analyzer on package %s: %v\n", pkg.Name, err)
+		return nil
+	}
+	pass.ResultOf[inspect.Analyzer] = result
+	_, err = ptrAnalyzer.Run(pass)
+	if err != nil {
+		return fmt.Errorf("Failed to run analyzer on package %s: %v\n", pkg.Name, err)
+	}
+	return nil
 }
@@ -19,14 +19,964 @@ Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/token"
+	"golang.org/x/tools/go/analysis"
+	"io"
+	"os"
+	"path/filepath"
+	"ptrcomp/analyzer"
+	"ptrcomp/report"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestPointerComparisonFinderWorking(t *testing.T) {
-	results, err := parseDir("./tests")
+	results, err := parseDir("./tests", analyzer.Options{})
 	assert.Nil(t, err)
 	assert.Equal(t, len(results), 1)
-	assert.True(t, strings.Contains(results[0], "ptrcmp/tests/with_pointer_comparison.go:25:5: comparing pointers to basic types: int and int\n"))
+	file, err := filepath.Abs("tests/with_pointer_comparison.go")
+	assert.Nil(t, err)
+	assert.Equal(t, file+":25:5: comparing pointers to basic types: int and int", results[0])
+}
+
+func TestIfHint(t *testing.T) {
+	results, err := parseDir("./testdata/src/ifhint", analyzer.Options{IfHint: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+
+	hint := "(if/else branches differ only by a constant; did you mean to compare the values?)"
+	assert.True(t, strings.Contains(results[0], "ifhint.go:23:5: comparing pointers to basic types: int and int "+hint))
+	assert.True(t, strings.Contains(results[1], "ifhint.go:32:5: comparing pointers to basic types: int and int "+hint))
+	assert.False(t, strings.Contains(results[2], hint))
+	assert.True(t, strings.Contains(results[3], "ifhint.go:47:5: comparing pointers to basic types: int and int "+hint))
+}
+
+func TestRelatedDeclarations(t *testing.T) {
+	pkgs, err := loadPackages(context.Background(), "./testdata/src/related", false, nil, driverConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(pkgs))
+
+	var related [][]string
+	err = analyzePackage(analyzer.NewPtrAnalyzer(), pkgs[0], os.ReadFile, func(d analysis.Diagnostic) {
+		var decls []string
+		for _, r := range d.Related {
+			pos := pkgs[0].Fset.Position(r.Pos)
+			decls = append(decls, fmt.Sprintf("%d:%d: %s", pos.Line, pos.Column, r.Message))
+		}
+		related = append(related, decls)
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, [][]string{
+		{"24:6: one declared here", "25:2: two declared here"},
+		{"23:13: param declared here", "21:5: global declared here"},
+		{"24:6: one declared here"},
+		{"24:6: one declared here"},
+	}, related)
+}
+
+func TestAliasesAreSameType(t *testing.T) {
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/aliases", analyzer.Options{}, driverConfig{}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(findings))
+	for _, f := range findings {
+		assert.Equal(t, "same-type", f.Rule, f.String())
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	target, err := filepath.Abs("./testdata/src/overlay/overlay.go")
+	assert.Nil(t, err)
+
+	buffer := filepath.Join(t.TempDir(), "buffer.go")
+	err = os.WriteFile(buffer, []byte("package overlay\n\nfunc compare(one, two *int) bool {\n\treturn one == two\n}\n"), 0o644)
+	assert.Nil(t, err)
+	spec := filepath.Join(t.TempDir(), "overlay.json")
+	err = os.WriteFile(spec, []byte(fmt.Sprintf(`{"Replace": {%q: %q}}`, target, buffer)), 0o644)
+	assert.Nil(t, err)
+
+	results, err := parseDir("./testdata/src/overlay", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+
+	var cfg driverConfig
+	cfg.overlay, err = readOverlay(spec)
+	assert.Nil(t, err)
+
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/overlay", analyzer.Options{}, cfg, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(findings))
+	assert.True(t, strings.Contains(findings[0].String(), "overlay.go:4:9: comparing pointers to basic types: int and int"))
+
+	// The overlay of one run doesn't carry over to the next.
+	var stdout, stderr strings.Builder
+	code := run([]string{"-overlay", spec, "./testdata/src/overlay"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "overlay.go:4:9"))
+	stdout.Reset()
+	code = run([]string{"./testdata/src/overlay"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+}
+
+func TestReadOverlayRejectsDeletion(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "overlay.json")
+	err := os.WriteFile(spec, []byte(`{"Replace": {"a.go": ""}}`), 0o644)
+	assert.Nil(t, err)
+
+	_, err = readOverlay(spec)
+	assert.NotNil(t, err)
+}
+
+func TestFindingsAcrossPackagesAreSorted(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		results, err := parseDir("./testdata/src/multipkg", analyzer.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, 4, len(results))
+		assert.True(t, strings.Contains(results[0], "multipkg/first/first.go:22:6: comparing pointers to basic types: int and int"))
+		assert.True(t, strings.Contains(results[1], "multipkg/first/first.go:26:6: comparing pointers to basic types: string and string"))
+		assert.True(t, strings.Contains(results[2], "multipkg/second/second.go:22:6: comparing pointers to basic types: int and int"))
+		assert.True(t, strings.Contains(results[3], "multipkg/second/second.go:26:6: comparing pointers to basic types: string and string"))
+	}
+}
+
+func TestFunctionsFromOtherPackages(t *testing.T) {
+	results, err := parseDir("./testdata/crosspkg", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(results))
+	assert.True(t, strings.Contains(results[0], "crosspkg/caller/caller.go:27:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[2], "crosspkg/caller/caller.go:29:6: comparing pointers to basic types: crosspkg/names.Name and crosspkg/names.Name"))
+}
+
+func TestWorkspaceModules(t *testing.T) {
+	results, err := parseDir("./testdata/workspace", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.True(t, strings.Contains(results[0], "workspace/a/a.go:22:9: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "workspace/b/b.go:22:9: comparing pointers to basic types: string and string"))
+}
+
+func TestOutsideModule(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	t.Setenv("GO111MODULE", "on")
+
+	_, err := parseDir(dir, analyzer.Options{})
+	assert.True(t, errors.Is(err, errNotInModule))
+
+	var stdout, stderr strings.Builder
+	code := run([]string{dir}, &stdout, &stderr)
+	assert.Equal(t, 2, code)
+	assert.True(t, strings.Contains(stderr.String(), dir+" is not inside a Go module; run from a module root or set GO111MODULE"))
+	assert.Equal(t, 2, run([]string{"-strict-exit", dir}, &stdout, &stderr))
+}
+
+func TestPathsAcrossInvocationDirectories(t *testing.T) {
+	nested, err := filepath.Abs("./testdata/src/multipkg/first")
+	assert.Nil(t, err)
+	want, err := parseDir(nested, analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(want))
+	for _, result := range want {
+		assert.True(t, strings.HasPrefix(result, filepath.Join(nested, "first.go")+":"), result)
+	}
+
+	results, err := parseDir("./testdata/src/multipkg/first", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, want, results)
+
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(filepath.Join("testdata", "src", "multipkg", "second")))
+	defer os.Chdir(wd)
+	for _, dir := range []string{"../first", "./../../multipkg/first/"} {
+		results, err := parseDir(dir, analyzer.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, want, results, dir)
+	}
+}
+
+func TestRelTo(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(filepath.Join("testdata", "src", "multipkg")))
+	defer os.Chdir(wd)
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-rel-to", "module", "-format", "json", "./first"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), `"file": "testdata/src/multipkg/first/first.go"`), stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"-rel-to", "cwd", "./first"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(stdout.String(), "first/first.go:22:6: "), stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"-rel-to", "cwd", "-compact", "../../../tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(stdout.String(), filepath.Join(wd, "tests")), stdout.String())
+
+	for _, args := range [][]string{{"-rel-to", "root"}, {"-rel-to", "module", "-diff"}, {"-rel-to", "cwd", "-context", "1"}} {
+		code = run(append(args, "./first"), &stdout, &stderr)
+		assert.Equal(t, 1, code, "%v", args)
+	}
+}
+
+func TestSortFindingsRemovesDuplicates(t *testing.T) {
+	at := func(file string, line, column int) finding {
+		return finding{Finding: report.Finding{Pos: token.Position{Filename: file, Line: line, Column: column}, Message: "comparing pointers to basic types: int and int"}}
+	}
+
+	sorted := sortFindings([]finding{at("b.go", 1, 1), at("a.go", 9, 2), at("a.go", 9, 1), at("b.go", 1, 1)})
+	assert.Equal(t, []finding{at("a.go", 9, 1), at("a.go", 9, 2), at("b.go", 1, 1)}, sorted)
+}
+
+func TestDeferAndGoStatements(t *testing.T) {
+	results, err := parseDir("./testdata/src/deferred", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+	assert.True(t, strings.Contains(results[0], "deferred.go:26:16: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "deferred.go:27:25: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[2], "deferred.go:31:13: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(results[3], "deferred.go:32:24: comparing pointers to basic types: string and string"))
+}
+
+func TestVariadicArgumentsAndSwitchCases(t *testing.T) {
+	results, err := parseDir("./testdata/src/variadic", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(results))
+	assert.True(t, strings.Contains(results[0], "variadic.go:26:14: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "variadic.go:26:22: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[4], "variadic.go:28:17: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[5], "variadic.go:33:7: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[6], "variadic.go:35:17: comparing pointers to basic types: string and string"))
+}
+
+func TestDisabledRuleIsNotReported(t *testing.T) {
+	results, err := parseDir("./tests", analyzer.Options{Rules: map[string]bool{"same-type": false}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+}
+
+func TestOptions(t *testing.T) {
+	results, err := parseDir("./testdata/src/options", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(results))
+	assert.True(t, strings.Contains(results[0], "options.go:24:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "options.go:25:6: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(results[2], "options.go:26:6: comparing pointers to basic types: time.Duration and time.Duration"))
+
+	results, err = parseDir("./testdata/src/options", analyzer.Options{Kinds: []string{"string", "int64"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.True(t, strings.Contains(results[0], "options.go:25:6: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(results[1], "options.go:26:6: comparing pointers to basic types: time.Duration and time.Duration"))
+
+	results, err = parseDir("./testdata/src/options", analyzer.Options{IgnoreTypes: []string{"time.Duration", "string"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "options.go:24:6: comparing pointers to basic types: int and int"))
+
+	results, err = parseDir("./testdata/src/options", analyzer.Options{FlagNil: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(results))
+	assert.True(t, strings.Contains(results[3], "options.go:27:6: comparing pointer to basic type with nil: int"))
+	assert.True(t, strings.Contains(results[4], "options.go:28:6: comparing pointer to basic type with nil: string"))
+
+	results, err = parseDir("./testdata/src/options", analyzer.Options{CheckTests: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+	assert.True(t, strings.Contains(results[3], "options_test.go:25:5: comparing pointers to basic types: int and int"))
+
+	_, err = parseDir("./testdata/src/options", analyzer.Options{Kinds: []string{"integer"}})
+	assert.NotNil(t, err)
+}
+
+func TestKindAliases(t *testing.T) {
+	results, err := parseDir("./testdata/src/kinds", analyzer.Options{Kinds: []string{"uintptr"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "kinds.go:22:6: comparing pointers to basic types: uintptr and uintptr"))
+
+	results, err = parseDir("./testdata/src/kinds", analyzer.Options{Kinds: []string{"byte", "int32"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.True(t, strings.Contains(results[0], "kinds.go:23:6: comparing pointers to basic types: byte and uint8"))
+	assert.True(t, strings.Contains(results[1], "kinds.go:24:6: comparing pointers to basic types: rune and int32"))
+
+	results, err = parseDir("./testdata/src/kinds", analyzer.Options{Kinds: []string{"int", "uint8"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "kinds.go:23:6: comparing pointers to basic types: byte and uint8"))
+
+	_, err = parseDir("./testdata/src/kinds", analyzer.Options{Kinds: []string{"any"}})
+	assert.NotNil(t, err)
+}
+
+func TestTupleElementsAreComparedIndependently(t *testing.T) {
+	results, err := parseDir("./testdata/src/tuples", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(results))
+	assert.True(t, strings.Contains(results[0], "tuples.go:34:5: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "tuples.go:34:29: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(results[4], "tuples.go:51:25: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(results[5], "tuples.go:51:45: comparing pointers to basic types: int and int"))
+}
+
+func TestEnclosingFunction(t *testing.T) {
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/functions", analyzer.Options{}, driverConfig{}, nil)
+	assert.Nil(t, err)
+	var functions []string
+	for _, f := range findings {
+		functions = append(functions, f.Function)
+	}
+	assert.Equal(t, []string{"", "Top", "(*Cache).Get", "Cache.Peek", "(*List[...]).Has", "Closures.func2.1", "Closures.func3"}, functions)
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-function", "./testdata/src/functions"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "functions.go:32:9: comparing pointers to basic types: int and int (in (*Cache).Get)\n"))
+}
+
+func TestDebug(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-debug", "-format=json", "-kinds=int", "./testdata/src/options"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	var findings []map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(stdout.String()), &findings))
+	assert.Equal(t, 1, len(findings))
+
+	trace := stderr.String()
+	assert.True(t, strings.Contains(trace, `level=DEBUG msg="analyzing package" package=ptrcomp/testdata/src/options files=1`))
+	assert.True(t, strings.Contains(trace, `options.go:24:6 op="==" left=*int right=*int decision=reported rule=same-type`))
+	assert.True(t, strings.Contains(trace, `options.go:25:6 op="!=" left=*string right=*string decision=skipped reason="string is excluded by -kinds or -ignore-types"`))
+	assert.True(t, strings.Contains(trace, `options.go:27:6 op="==" left=*int right="untyped nil" decision=skipped reason="rule nil is disabled"`))
+
+	stderr.Reset()
+	code = run([]string{"-format=json", "./testdata/src/options"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stderr.String())
+}
+
+func TestRelatedLocations(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-format=json", "./testdata/src/related"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	var findings []struct {
+		Line    int
+		Related []struct {
+			File    string
+			Line    int
+			Message string
+		}
+	}
+	assert.Nil(t, json.Unmarshal([]byte(stdout.String()), &findings))
+	assert.Equal(t, 4, len(findings))
+	assert.Equal(t, 2, len(findings[0].Related))
+	assert.Equal(t, 24, findings[0].Related[0].Line)
+	assert.True(t, strings.HasSuffix(findings[0].Related[0].File, "related.go"))
+	assert.Equal(t, "one declared here", findings[0].Related[0].Message)
+}
+
+func TestIdentityTypesAreOptIn(t *testing.T) {
+	results, err := parseDir("./testdata/src/identity", analyzer.Options{IdentityTypes: []string{"ptrcomp/testdata/src/identity.Entry"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-flag-value-compare-of-identity-types", "-identity-types=ptrcomp/testdata/src/identity.Entry", "./testdata/src/identity"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 2, strings.Count(stdout.String(), "identity type ptrcomp/testdata/src/identity.Entry"))
+	assert.False(t, strings.Contains(stdout.String(), "identity.Token"))
+}
+
+func TestOrderedComparisons(t *testing.T) {
+	for _, opts := range []analyzer.Options{{IncludeOrdered: true}, {Rules: map[string]bool{"ordered": true}}} {
+		findings, _, err := analyzeDir(context.Background(), "./testdata/src/ordered", opts, driverConfig{}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(findings))
+		assert.True(t, strings.Contains(findings[0].String(), "ordered.go:23:9: ordering pointers to basic types does not compile: int and int"))
+		assert.Equal(t, "ordered", findings[0].Rule)
+	}
+
+	for _, opts := range []analyzer.Options{{}, {IncludeOrdered: true, Rules: map[string]bool{"ordered": false}}} {
+		results, err := parseDir("./testdata/src/ordered", opts)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(results))
+	}
+
+	results, err := parseDir("./testdata/src/ordered", analyzer.Options{IncludeOrdered: true, ReportOperator: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "ordered.go:23:9: ordering pointers to basic types with '<' does not compile: int and int"))
+}
+
+func TestOrderedUintptrComparisons(t *testing.T) {
+	results, err := parseDir("./testdata/src/uintptrorder", analyzer.Options{CheckUnsafe: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+
+	results, err = parseDir("./testdata/src/uintptrorder", analyzer.Options{CheckUnsafe: true, IncludeOrdered: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "uintptrorder.go:26:9: comparing addresses of pointers to basic types through uintptr: int and int"))
+}
+
+func TestPartiallyTypeCheckedFiles(t *testing.T) {
+	results, err := parseDir("./testdata/src/partial", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.True(t, strings.Contains(results[0], "broken.go:24:9: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "clean.go:22:9: comparing pointers to basic types: string and string"))
+
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/partial", analyzer.Options{}, driverConfig{skipErroredFiles: true}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(findings))
+	assert.True(t, strings.Contains(findings[0].String(), "clean.go:22:9: comparing pointers to basic types: string and string"))
+}
+
+func TestMapElementAddresses(t *testing.T) {
+	// &m[k] doesn't compile, so it is skipped rather than reported or
+	// crashing the analyzer, while pointers stored in a map are reported.
+	results, err := parseDir("./testdata/src/mapelems", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "mapelems.go:29:9: comparing pointers to basic types from the same map m: int and int; did you mean *m[k] == *m[j]?"))
+}
+
+func TestGenerics(t *testing.T) {
+	results, err := parseDir("./testdata/src/generics", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(results))
+	assert.True(t, strings.Contains(results[0], "generics.go:40:9: comparing pointers to basic types: T and T"))
+	assert.True(t, strings.Contains(results[1], "generics.go:44:9: comparing pointers to basic types: T and T"))
+	// Variables only ever assigned new(T) are separate allocations.
+	assert.True(t, strings.Contains(results[2], "generics.go:64:6: comparing against a freshly allocated pointer to T from new; always false"))
+
+	results, err = parseDir("./testdata/src/generics", analyzer.Options{Kinds: []string{"float64"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+	assert.True(t, strings.Contains(results[0], "generics.go:44:9"))
+}
+
+func TestGenericMethodResults(t *testing.T) {
+	results, err := parseDir("./testdata/src/genericmethods", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 8, len(results))
+	assert.True(t, strings.Contains(results[0], "genericmethods.go:42:6: comparing pointers to basic types: int and int"))
+
+	results, err = parseDir("./testdata/src/genericmethods", analyzer.Options{Kinds: []string{"string"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+}
+
+func TestGeneratedFindings(t *testing.T) {
+	results, err := parseDir("./testdata/src/generated", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.True(t, strings.Contains(results[0], "hand.go:22:9: comparing pointers to basic types: int and int"))
+
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/generated", analyzer.Options{}, driverConfig{generatedInfo: true}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(findings))
+	assert.Equal(t, generated, findings[0].Category)
+	assert.Equal(t, report.SeverityInfo, findings[0].Severity)
+	assert.True(t, strings.Contains(findings[0].String(), "gen.go:6:9: info: comparing pointers to basic types: int and int"))
+	assert.Equal(t, firstParty, findings[1].Category)
+	assert.Equal(t, report.SeverityError, findings[1].Severity)
+
+	findings, _, err = analyzeDir(context.Background(), "./testdata/src/generated", analyzer.Options{}, driverConfig{checkGenerated: true}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(findings))
+	assert.Equal(t, report.SeverityError, findings[0].Severity)
+	assert.True(t, strings.Contains(findings[0].String(), "gen.go:6:9: comparing pointers to basic types: int and int"))
+}
+
+// TestGeneratedFindingsAndExitCode checks that findings in generated files
+// only affect the exit code with -check-generated: otherwise they are dropped,
+// or reported at info severity with -generated-info, which never fails.
+func TestGeneratedFindingsAndExitCode(t *testing.T) {
+	tests := []struct {
+		flags    []string
+		code     int
+		printed  string
+		findings int
+	}{
+		{nil, 0, "", 0},
+		{[]string{"-check-generated"}, 1, "gen.go:6:9: comparing pointers to basic types: int and int", 1},
+		{[]string{"-generated-info"}, 0, "gen.go:6:9: info: comparing pointers to basic types: int and int", 1},
+		{[]string{"-check-generated", "-generated-info"}, 1, "gen.go:6:9: comparing pointers to basic types: int and int", 1},
+	}
+	for _, test := range tests {
+		for _, mode := range [][]string{{"-fail-fast"}, {"-expect", "testdata/src/generatedonly/none.json"}} {
+			var stdout, stderr strings.Builder
+			args := append(append(slices.Clone(test.flags), mode...), "./testdata/src/generatedonly")
+			code := run(args, &stdout, &stderr)
+			assert.Equal(t, test.code, code, args)
+			// -expect only prints differences, and info findings are
+			// never expected.
+			if test.printed == "" || (mode[0] == "-expect" && test.code == 0) {
+				assert.Equal(t, "", stdout.String(), args)
+			} else {
+				assert.True(t, strings.Contains(stdout.String(), test.printed), args)
+			}
+		}
+
+		var stdout, stderr strings.Builder
+		code := run(append(slices.Clone(test.flags), "-stats", "./testdata/src/generatedonly"), &stdout, &stderr)
+		assert.Equal(t, 0, code)
+		assert.True(t, strings.Contains(stderr.String(), fmt.Sprintf(`"findings":%d`, test.findings)), test.flags)
+	}
+}
+
+func TestFileCategory(t *testing.T) {
+	file := &ast.File{}
+	assert.Equal(t, vendored, fileCategory(filepath.Join("repo", "vendor", "example.com", "dep", "dep.go"), file))
+	assert.Equal(t, firstParty, fileCategory(filepath.Join("repo", "vendors", "dep.go"), file))
+}
+
+func TestInterfaceMethodResults(t *testing.T) {
+	results, err := parseDir("./testdata/src/ifaces", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(results))
+	assert.True(t, strings.Contains(results[0], "ifaces.go:31:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[1], "ifaces.go:32:6: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(results[2], "ifaces.go:33:6: comparing pointers to basic types: string and string"))
+}
+
+func TestFastLoad(t *testing.T) {
+	fast, _, err := analyzeDir(context.Background(), "./testdata/src/functions", analyzer.Options{}, driverConfig{fastLoad: true}, nil)
+	assert.Nil(t, err)
+	want, _, err := analyzeDir(context.Background(), "./testdata/src/functions", analyzer.Options{}, driverConfig{}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, want, fast)
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-fast", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stderr.String(), "findings may be missing"))
+	assert.True(t, strings.Contains(stdout.String(), "with_pointer_comparison.go:25:5"))
+}
+
+func TestAllPlatforms(t *testing.T) {
+	cfg := driverConfig{platforms: []string{"linux/amd64"}}
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/platforms", analyzer.Options{}, cfg, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(findings))
+	assert.True(t, strings.Contains(findings[0].String(), "platforms_linux.go:22:9"))
+	assert.True(t, strings.Contains(findings[1].String(), "shared.go:22:9"))
+
+	// The shared file is built on both platforms but reported once, also
+	// when streaming.
+	cfg.platforms = []string{"linux/amd64", "windows/amd64"}
+	var emitted []string
+	findings, _, err = analyzeDir(context.Background(), "./testdata/src/platforms", analyzer.Options{}, cfg, func(pkgFindings []finding) bool {
+		for _, f := range pkgFindings {
+			emitted = append(emitted, filepath.Base(f.Pos.Filename))
+		}
+		return true
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(findings))
+	assert.True(t, strings.Contains(findings[1].String(), "platforms_windows.go:22:9"))
+	assert.Equal(t, []string{"platforms_linux.go", "shared.go", "platforms_windows.go"}, emitted)
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-platforms", "linux/amd64", "./testdata/src/platforms"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.Contains(stderr.String(), "-platforms requires -all-platforms"))
+
+	stderr.Reset()
+	code = run([]string{"-all-platforms", "-platforms", "linux", "./testdata/src/platforms"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.Contains(stderr.String(), `invalid -platforms entry "linux"`))
+}
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := analyzeDir(ctx, "./tests", analyzer.Options{}, driverConfig{}, nil)
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "failed to load packages: context canceled"))
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	_, _, err = analyzeDir(ctx, "./tests", analyzer.Options{}, driverConfig{}, nil)
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "timed out (see -timeout)"))
+}
+
+func TestStreamEmitsPerPackage(t *testing.T) {
+	var emitted [][]string
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/multipkg", analyzer.Options{}, driverConfig{}, func(pkgFindings []finding) bool {
+		var batch []string
+		for _, f := range pkgFindings {
+			batch = append(batch, filepath.Base(f.Pos.Filename))
+		}
+		emitted = append(emitted, batch)
+		return true
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(findings))
+	assert.ElementsMatch(t, [][]string{{"first.go", "first.go"}, {"second.go", "second.go"}}, emitted)
+}
+
+func TestProgress(t *testing.T) {
+	var line strings.Builder
+	cfg := driverConfig{progress: newProgressLine(&line)}
+	cfg.progress.interval = 0
+
+	_, _, err := analyzeDir(context.Background(), "./testdata/src/multipkg", analyzer.Options{}, cfg, nil)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(line.String(), "\ranalyzed 0/2 packages"))
+	assert.True(t, strings.HasSuffix(line.String(), "\ranalyzed 2/2 packages"))
+	cfg.progress.clear()
+	assert.True(t, strings.HasSuffix(line.String(), "\r\x1b[K"))
+
+	// stderr isn't a terminal, so -progress writes nothing.
+	var stdout, stderr strings.Builder
+	code := run([]string{"-progress", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stderr.String())
+}
+
+func TestFailFast(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-fail-fast", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, 1, strings.Count(stdout.String(), "\n"))
+	assert.True(t, strings.Contains(stdout.String(), "comparing pointers to basic types"))
+
+	emitted := 0
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/multipkg", analyzer.Options{}, driverConfig{}, func([]finding) bool {
+		emitted++
+		return false
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, emitted)
+	assert.Equal(t, 2, len(findings))
+
+	stdout.Reset()
+	code = run([]string{"-fail-fast", "./testdata/src/overlay"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+}
+
+func TestUintptrConversions(t *testing.T) {
+	results, err := parseDir("./testdata/src/uintptrs", analyzer.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+
+	results, err = parseDir("./testdata/src/uintptrs", analyzer.Options{CheckUnsafe: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(results))
+	assert.True(t, strings.Contains(results[0], "uintptrs.go:26:6: comparing addresses of pointers to basic types through uintptr: int and int"))
+	assert.True(t, strings.Contains(results[1], "uintptrs.go:27:6: comparing addresses of pointers to basic types through uintptr: int and int"))
+	assert.True(t, strings.Contains(results[2], "uintptrs.go:34:6: comparing pointers to basic types computed with unsafe pointer arithmetic: int and int"))
+}
+
+func TestQuietClean(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-quiet-clean", "-stats", "./testdata/src/overlay"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+	assert.Equal(t, "", stderr.String())
+
+	code = run([]string{"-quiet-clean", "-format=json", "./testdata/src/overlay"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"-stats", "./testdata/src/overlay"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+	assert.True(t, strings.Contains(stderr.String(), `"findings":0`))
+
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"-quiet-clean", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "with_pointer_comparison.go:25:5: comparing pointers to basic types: int and int"))
+}
+
+func TestCollapseDuplicates(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-collapse-duplicates", "-stats", "./testdata/src/duplicates"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.True(t, strings.HasSuffix(lines[0], "duplicates.go:22:5: comparing pointers to basic types: int and int (repeated 2 more times in this file)"))
+	assert.True(t, strings.HasSuffix(lines[1], "duplicates.go:28:5: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.HasSuffix(lines[2], "duplicates.go:34:5: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(stderr.String(), `"findings":5`))
+}
+
+func TestOncePerLine(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-once-per-line", "-stats", "./testdata/src/sameline"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.True(t, strings.HasSuffix(lines[0], "sameline.go:22:5: comparing pointers to basic types: int and int (1 more finding on this line)"))
+	assert.True(t, strings.HasSuffix(lines[1], "sameline.go:25:5: comparing pointers to basic types: int and int (2 more findings on this line)"))
+	assert.True(t, strings.HasSuffix(lines[2], "sameline.go:28:9: comparing pointers to basic types: string and string"))
+	assert.True(t, strings.Contains(stderr.String(), `"findings":6`))
+}
+
+func TestFailOnRule(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"-fail-on-rule", "same-type", "./tests"}, 1},
+		{[]string{"-fail-on-rule", "cross-type,nil", "./tests"}, 0},
+		{[]string{"-fail-on-rule", "nil", "-flag-nil", "./testdata/src/typednil"}, 1},
+		{[]string{"-fail-on-rule", "cross-type", "-flag-nil", "./testdata/src/typednil"}, 0},
+		{[]string{"-fail-on-rule", "nil", "-strict-exit", "./tests"}, 0},
+		{[]string{"-fail-on-rule=cross-type,address-of", "./tests"}, 0},
+		{[]string{"-fail-on-rule=cross-type,address-of", "./testdata/src/addressof"}, 1},
+		{[]string{"-fail-on-rule", "same-type,self", "./testdata/src/selfcompare"}, 1},
+		{[]string{"-fail-on-rule", "addressof", "./tests"}, 1},
+		{[]string{"-fail-on-rule", "addressof", "-strict-exit", "./tests"}, 2},
+	} {
+		var stdout, stderr strings.Builder
+		assert.Equal(t, tt.code, run(tt.args, &stdout, &stderr), strings.Join(tt.args, " "))
+	}
+
+	// Findings of other rules are still printed, as warnings.
+	var stdout, stderr strings.Builder
+	run([]string{"-fail-on-rule", "cross-type", "./tests"}, &stdout, &stderr)
+	assert.True(t, strings.Contains(stdout.String(), "with_pointer_comparison.go:25:5: warning: comparing pointers to basic types: int and int"))
+	run([]string{"-fail-on-rule", "cross-type", "-format", "json", "./tests"}, &stdout, &stderr)
+	assert.True(t, strings.Contains(stdout.String(), `"severity": "warning"`))
+	stdout.Reset()
+	run([]string{"-fail-on-rule=cross-type,address-of", "./testdata/src/addressof"}, &stdout, &stderr)
+	assert.True(t, strings.Contains(stdout.String(), "addressof.go:30:6: comparing pointers to basic types: int and int\n"))
+	assert.True(t, strings.Contains(stdout.String(), "addressof.go:29:6: warning: comparing pointers to basic types: int and int\n"))
+}
+
+func TestStrictExit(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"./tests"}, 0},
+		{[]string{"-strict-exit", "./tests"}, 1},
+		{[]string{"-strict-exit", "-kinds", "complex128", "./tests"}, 0},
+		{[]string{"-strict-exit", "./testdata/src/generatedonly"}, 0},
+		{[]string{"-strict-exit"}, 2},
+		{[]string{"-strict-exit", "-color", "sometimes", "./tests"}, 2},
+		{[]string{"-strict-exit", "-unknown-flag", "./tests"}, 2},
+		{[]string{"-unknown-flag", "./tests"}, 1},
+		{[]string{"-color", "sometimes", "./tests"}, 1},
+		{[]string{"-strict-exit", "-timeout", "1ns", "./tests"}, 3},
+		{[]string{"-timeout", "1ns", "./tests"}, 1},
+	} {
+		var stdout, stderr strings.Builder
+		assert.Equal(t, tt.code, run(tt.args, &stdout, &stderr), "%v", tt.args)
+	}
+
+	// Output that can't be written fails like analysis does.
+	var stderr strings.Builder
+	assert.Equal(t, 3, run([]string{"-strict-exit", "./tests"}, failingWriter{}, &stderr))
+	assert.Equal(t, 1, run([]string{"./tests"}, failingWriter{}, &stderr))
+	assert.True(t, strings.Contains(stderr.String(), "Error write failed"), stderr.String())
+
+	// So do packages that can't be analyzed, even with findings elsewhere.
+	defer func() { newAnalyzer = analyzer.NewPtrAnalyzerWithOptions }()
+	newAnalyzer = func(opts analyzer.Options) *analysis.Analyzer {
+		a := analyzer.NewPtrAnalyzerWithOptions(opts)
+		run := a.Run
+		a.Run = func(pass *analysis.Pass) (any, error) {
+			if pass.Pkg.Name() == "first" {
+				panic("pathological package")
+			}
+			return run(pass)
+		}
+		return a
+	}
+	var stdout strings.Builder
+	assert.Equal(t, 3, run([]string{"-strict-exit", "./testdata/src/multipkg"}, &stdout, &stderr))
+	assert.True(t, strings.Contains(stdout.String(), "multipkg/second/second.go:22:6"))
+	assert.Equal(t, 1, run([]string{"./testdata/src/multipkg"}, &stdout, &stderr))
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestPanickingPackageIsSkipped(t *testing.T) {
+	defer func() { newAnalyzer = analyzer.NewPtrAnalyzerWithOptions }()
+	newAnalyzer = func(opts analyzer.Options) *analysis.Analyzer {
+		a := analyzer.NewPtrAnalyzerWithOptions(opts)
+		run := a.Run
+		a.Run = func(pass *analysis.Pass) (any, error) {
+			if pass.Pkg.Name() == "first" {
+				panic("pathological package")
+			}
+			return run(pass)
+		}
+		return a
+	}
+
+	findings, _, err := analyzeDir(context.Background(), "./testdata/src/multipkg", analyzer.Options{}, driverConfig{}, nil)
+	var failed *failedPackagesError
+	assert.True(t, errors.As(err, &failed))
+	assert.Equal(t, []string{"ptrcomp/testdata/src/multipkg/first"}, failed.Packages)
+	assert.Equal(t, 2, len(findings))
+	assert.True(t, strings.Contains(findings[0].String(), "multipkg/second/second.go:22:6: comparing pointers to basic types: int and int"))
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.Contains(stdout.String(), "multipkg/second/second.go:26:6"))
+	assert.True(t, strings.Contains(stderr.String(), "panic analyzing package ptrcomp/testdata/src/multipkg/first: pathological package\ngoroutine "), stderr.String())
+	code = run([]string{"-strict-exit", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 3, code)
+	assert.True(t, strings.Contains(stderr.String(), "analysis failed for 1 package(s): ptrcomp/testdata/src/multipkg/first"))
+}
+
+func TestLimit(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-limit", "2", "-stats", "./testdata/src/duplicates"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasSuffix(lines[0], "duplicates.go:22:5: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.HasSuffix(lines[1], "duplicates.go:25:5: comparing pointers to basic types: int and int"))
+	assert.True(t, strings.Contains(stderr.String(), "3 more findings not shown (see -limit)"))
+	assert.True(t, strings.Contains(stderr.String(), `"findings":5`))
+
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"-limit", "3", "-stream", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 3, strings.Count(stdout.String(), "\n"))
+	assert.True(t, strings.Contains(stderr.String(), "1 more finding not shown (see -limit)"))
+
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"-limit", "1", "-compact", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasSuffix(stdout.String(), "first.go: 2 pointer comparisons at lines 22,26\n"), stdout.String())
+	assert.Equal(t, 1, strings.Count(stdout.String(), "\n"))
+	assert.True(t, strings.Contains(stderr.String(), "1 more file not shown (see -limit)"))
+}
+
+// fakeReporter captures findings instead of writing them.
+type fakeReporter struct {
+	findings []report.Finding
+	flushed  int
+}
+
+func (r *fakeReporter) Report(f report.Finding) { r.findings = append(r.findings, f) }
+
+func (r *fakeReporter) Flush() error {
+	r.flushed++
+	return nil
+}
+
+func TestRunReportsToReporter(t *testing.T) {
+	fake := &fakeReporter{}
+	defer func(orig func(string, io.Writer, bool) (report.Reporter, error)) { newReporter = orig }(newReporter)
+	newReporter = func(string, io.Writer, bool) (report.Reporter, error) { return fake, nil }
+
+	for _, args := range [][]string{{"./testdata/src/multipkg"}, {"-stream", "./testdata/src/multipkg"}} {
+		fake.findings, fake.flushed = nil, 0
+		var stdout, stderr strings.Builder
+		code := run(args, &stdout, &stderr)
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, 1, fake.flushed)
+		assert.Equal(t, 4, len(fake.findings))
+		assert.Equal(t, "same-type", fake.findings[0].Rule)
+		assert.Equal(t, report.SeverityError, fake.findings[0].Severity)
+	}
+
+	// Paths that print without the reporter still flush it once.
+	expect := filepath.Join(t.TempDir(), "expected.json")
+	assert.Nil(t, os.WriteFile(expect, []byte("[]\n"), 0o644))
+	for _, args := range [][]string{
+		{"-compact", "./testdata/src/multipkg"},
+		{"-diff", "./testdata/src/multipkg"},
+		{"-expect=" + expect, "./testdata/src/multipkg"},
+		{"-quiet-clean", "./testdata/src/overlay"},
+	} {
+		fake.findings, fake.flushed = nil, 0
+		var stdout, stderr strings.Builder
+		run(args, &stdout, &stderr)
+		assert.Equal(t, 1, fake.flushed, args[0])
+	}
+}
+
+func TestFormat(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-format", "json", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), `"message": "comparing pointers to basic types: int and int"`))
+
+	stdout.Reset()
+	code = run([]string{"-format", "sarif", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), `"ruleId": "same-type"`))
+
+	stdout.Reset()
+	code = run([]string{"-format", "junit", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), `<failure message="comparing pointers to basic types: int and int" type="same-type">`))
+
+	code = run([]string{"-format", "xml", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	code = run([]string{"-format", "json", "-compact", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+}
+
+func TestTemplateFormat(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "finding.tmpl")
+	assert.Nil(t, os.WriteFile(name, []byte("{{.Line}}:{{.Col}} {{.RuleID}} {{.Left}} {{.Op}} {{.Right}}\n"), 0o644))
+	var stdout, stderr strings.Builder
+	code := run([]string{"-format", "template", "-template-file", name, "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "25:5 same-type *int == *int\n"))
+
+	assert.Nil(t, os.WriteFile(name, []byte("{{.Unknown}}"), 0o644))
+	stdout.Reset()
+	code = run([]string{"-format", "template", "-template-file", name, "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "", stdout.String())
+
+	for _, args := range [][]string{{"-format", "template"}, {"-template-file", name}} {
+		code = run(append(args, "./tests"), &stdout, &stderr)
+		assert.Equal(t, 1, code)
+	}
+}
+
+func TestContext(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-context", "1", "./tests"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasSuffix(stdout.String(), "with_pointer_comparison.go:25:5: comparing pointers to basic types: int and int\n"+
+		"  24 | \n"+
+		"> 25 | \tif one == two {\n"+
+		"  26 | \t\t// linter should highlight as comparisons between two \"basic\" ptrs i.e *int == *int\n"))
+
+	for _, args := range [][]string{{"-context", "1", "-format", "json"}, {"-context", "1", "-compact"}} {
+		code = run(append(args, "./tests"), &stdout, &stderr)
+		assert.Equal(t, 1, code)
+	}
 }
@@ -0,0 +1,293 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"ptrcomp/analyzer"
+	"ptrcomp/report"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"unicode/utf8"
+)
+
+// colorEnabled resolves the -color mode for out. In auto mode color is only
+// used when out is a terminal and NO_COLOR is unset, so piped output and CI
+// logs never contain escape codes.
+func colorEnabled(mode string, out io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+		f, ok := out.(*os.File)
+		return ok && isTerminal(f), nil
+	default:
+		return false, fmt.Errorf("invalid -color value %q: must be auto, always or never", mode)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// quietWriter drops everything written to it while quiet is set, for
+// -quiet-clean.
+type quietWriter struct {
+	w     io.Writer
+	quiet bool
+}
+
+func (q *quietWriter) Write(p []byte) (int, error) {
+	if q.quiet {
+		return len(p), nil
+	}
+	return q.w.Write(p)
+}
+
+// newReporter returns the reporter for -format writing to w. Tests replace it.
+var newReporter = func(format string, w io.Writer, color bool) (report.Reporter, error) {
+	switch format {
+	case "text":
+		return report.NewText(w, color), nil
+	case "json":
+		return report.NewJSON(w), nil
+	case "sarif":
+		return report.NewSARIF(w, toolVersion()), nil
+	case "junit":
+		return report.NewJUnit(w), nil
+	default:
+		return nil, fmt.Errorf("invalid -format value %q: must be text, json, sarif, junit or template", format)
+	}
+}
+
+// newTemplateReporter returns the reporter for -format=template, executing
+// the text/template in the named file for each finding.
+func newTemplateReporter(name string, w io.Writer) (report.Reporter, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(name)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return report.NewTemplate(w, tmpl)
+}
+
+// relativePaths returns findings with their file names made relative for
+// -rel-to: to the working directory for "cwd", or to the root of their module
+// for "module". Files outside that directory keep their absolute names, and
+// findings are returned unchanged for any other mode.
+func relativePaths(findings []finding, mode string) []finding {
+	var base string
+	switch mode {
+	case "cwd":
+		wd, err := os.Getwd()
+		if err != nil {
+			return findings
+		}
+		base = wd
+	case "module":
+	default:
+		return findings
+	}
+	relative := make([]finding, len(findings))
+	for i, f := range findings {
+		if mode == "module" {
+			base = f.ModuleDir
+		}
+		if base != "" {
+			f.Pos.Filename = relativePath(base, f.Pos.Filename)
+			f.Related = slices.Clone(f.Related)
+			for j := range f.Related {
+				f.Related[j].Pos.Filename = relativePath(base, f.Related[j].Pos.Filename)
+			}
+		}
+		relative[i] = f
+	}
+	return relative
+}
+
+// relativePath returns name relative to base, or name itself if it is
+// outside base.
+func relativePath(base, name string) string {
+	rel, err := filepath.Rel(base, name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return name
+	}
+	return rel
+}
+
+// posixPaths returns findings with forward slashes as the separator in their
+// file names for -path-style=posix.
+func posixPaths(findings []finding) []finding {
+	posix := make([]finding, len(findings))
+	for i, f := range findings {
+		f.Pos.Filename = filepath.ToSlash(f.Pos.Filename)
+		f.Related = slices.Clone(f.Related)
+		for j := range f.Related {
+			f.Related[j].Pos.Filename = filepath.ToSlash(f.Related[j].Pos.Filename)
+		}
+		posix[i] = f
+	}
+	return posix
+}
+
+// escapeFindings returns findings with invalid UTF-8 in their messages,
+// functions and operand types replaced, and with ascii also every non-ASCII
+// character escaped, e.g. "Größe" as "Gr\u00f6\u00dfe". File names are left
+// alone, since they are used to read the files.
+func escapeFindings(findings []finding, ascii bool) []finding {
+	escape := func(s string) string {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+		if !ascii {
+			return s
+		}
+		var b strings.Builder
+		for _, r := range s {
+			switch {
+			case r < utf8.RuneSelf:
+				b.WriteRune(r)
+			case r > 0xffff:
+				fmt.Fprintf(&b, "\\U%08x", r)
+			default:
+				fmt.Fprintf(&b, "\\u%04x", r)
+			}
+		}
+		return b.String()
+	}
+	escaped := make([]finding, len(findings))
+	for i, f := range findings {
+		f.Message, f.Function, f.Left, f.Right = escape(f.Message), escape(f.Function), escape(f.Left), escape(f.Right)
+		f.Related = slices.Clone(f.Related)
+		for j := range f.Related {
+			f.Related[j].Message = escape(f.Related[j].Message)
+		}
+		escaped[i] = f
+	}
+	return escaped
+}
+
+// writeResults passes findings to r in order.
+func writeResults(r report.Reporter, findings []finding) {
+	for _, f := range findings {
+		r.Report(f.Finding)
+	}
+}
+
+// writeCompact prints one line per file with the number of findings and the
+// distinct lines they are on, in the order files were first reported. If
+// limit is positive at most that many files are printed, and it returns how
+// many more there were.
+func writeCompact(w io.Writer, findings []finding, color bool, limit int) int {
+	var files []string
+	lines := make(map[string][]int)
+	counts := make(map[string]int)
+	for _, f := range findings {
+		name := f.Pos.Filename
+		if counts[name] == 0 {
+			files = append(files, name)
+		}
+		counts[name]++
+		if !slices.Contains(lines[name], f.Pos.Line) {
+			lines[name] = append(lines[name], f.Pos.Line)
+		}
+	}
+
+	var hidden int
+	if limit > 0 && len(files) > limit {
+		files, hidden = files[:limit], len(files)-limit
+	}
+	for _, name := range files {
+		noun, at := "pointer comparisons", "lines"
+		if counts[name] == 1 {
+			noun = "pointer comparison"
+		}
+		if len(lines[name]) == 1 {
+			at = "line"
+		}
+		slices.Sort(lines[name])
+		numbers := make([]string, 0, len(lines[name]))
+		for _, line := range lines[name] {
+			numbers = append(numbers, strconv.Itoa(line))
+		}
+		label := name
+		if color {
+			label = report.ANSIBold + name + report.ANSIReset
+		}
+		fmt.Fprintf(w, "%s: %d %s at %s %s\n", label, counts[name], noun, at, strings.Join(numbers, ","))
+	}
+	return hidden
+}
+
+// writeTypeSummary prints how often each distinct combination of operand
+// types and operator was reported, most frequent first, e.g.
+// "*int == *int: 42 times". Findings without operand types are left out.
+func writeTypeSummary(w io.Writer, findings []finding) {
+	counts := make(map[string]int)
+	var pairs []string
+	for _, f := range findings {
+		if f.Op == "" {
+			continue
+		}
+		pair := f.Left + " " + f.Op + " " + f.Right
+		if counts[pair] == 0 {
+			pairs = append(pairs, pair)
+		}
+		counts[pair]++
+	}
+	slices.SortFunc(pairs, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+	for _, pair := range pairs {
+		noun := "times"
+		if counts[pair] == 1 {
+			noun = "time"
+		}
+		fmt.Fprintf(w, "%s: %d %s\n", pair, counts[pair], noun)
+	}
+}
+
+func listRules(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range analyzer.Rules {
+		state := "off"
+		if r.Default {
+			state = "on"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.ID, state, r.Doc)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,159 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"ptrcomp/analyzer"
+	"ptrcomp/report"
+	"strings"
+	"testing"
+)
+
+func TestColorDisabledWhenPiped(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+
+	r, w, err := os.Pipe()
+	assert.Nil(t, err)
+	defer r.Close()
+
+	color, err := colorEnabled("auto", w)
+	assert.Nil(t, err)
+	assert.False(t, color)
+
+	findings, _, err := analyzeDir(context.Background(), "./tests", analyzer.Options{}, driverConfig{}, nil)
+	assert.Nil(t, err)
+	writeResults(report.NewText(w, color), findings)
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(out), "comparing pointers to basic types: int and int"))
+	assert.False(t, strings.Contains(string(out), "\x1b["))
+}
+
+func TestColorModes(t *testing.T) {
+	color, err := colorEnabled("always", os.Stdout)
+	assert.Nil(t, err)
+	assert.True(t, color)
+
+	color, err = colorEnabled("never", os.Stdout)
+	assert.Nil(t, err)
+	assert.False(t, color)
+
+	_, err = colorEnabled("sometimes", os.Stdout)
+	assert.NotNil(t, err)
+
+	var out strings.Builder
+	f := finding{Finding: report.Finding{Pos: token.Position{Filename: "a.go", Line: 1, Column: 2}, Message: "comparing pointers to basic types: int and int"}}
+	writeResults(report.NewText(&out, true), []finding{f})
+	assert.Equal(t, "\x1b[1ma.go:1:2\x1b[0m: \x1b[33mcomparing pointers to basic types: int and int\x1b[0m\n", out.String())
+}
+
+func TestWriteCompact(t *testing.T) {
+	at := func(file string, line int) finding {
+		return finding{Finding: report.Finding{Pos: token.Position{Filename: file, Line: line, Column: 5}, Message: "comparing pointers to basic types: int and int"}}
+	}
+	findings := []finding{at("foo.go", 12), at("foo.go", 25), at("bar.go", 7), at("foo.go", 30), at("foo.go", 30)}
+
+	var out strings.Builder
+	assert.Equal(t, 0, writeCompact(&out, findings, false, 0))
+	assert.Equal(t, "foo.go: 4 pointer comparisons at lines 12,25,30\nbar.go: 1 pointer comparison at line 7\n", out.String())
+
+	// The limit applies to files, whose findings are all counted.
+	out.Reset()
+	assert.Equal(t, 1, writeCompact(&out, findings, false, 1))
+	assert.Equal(t, "foo.go: 4 pointer comparisons at lines 12,25,30\n", out.String())
+}
+
+func TestWriteTypeSummary(t *testing.T) {
+	compared := func(left, op, right string) finding {
+		return finding{Finding: report.Finding{Op: op, Left: left, Right: right}}
+	}
+	findings := []finding{
+		compared("*string", "!=", "*string"),
+		compared("*int", "==", "*int"),
+		compared("*int", "==", "*int"),
+		compared("*bool", "==", "*bool"),
+		compared("*int", "!=", "*int"),
+		{},
+	}
+
+	var out strings.Builder
+	writeTypeSummary(&out, findings)
+	assert.Equal(t, "*int == *int: 2 times\n*bool == *bool: 1 time\n*int != *int: 1 time\n*string != *string: 1 time\n", out.String())
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-type-summary", "./testdata/src/options"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "*int == *int: 1 time\n*string != *string: 1 time\n*time.Duration == *time.Duration: 1 time\n", stderr.String())
+	assert.False(t, strings.Contains(stdout.String(), " time\n"))
+}
+
+func TestListRules(t *testing.T) {
+	var out strings.Builder
+	listRules(&out)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(analyzer.Rules), len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "same-type   on   comparisons between pointers to the same basic type"))
+	assert.True(t, strings.HasPrefix(lines[8], "if-hint     off  hint"))
+}
+
+func TestASCIIOnly(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"./testdata/src/unicodenames"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), "unicodenames.Größe and ptrcomp/testdata/src/unicodenames.Größe\n"), stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"-ascii-only", "-function", "./testdata/src/unicodenames"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.Contains(stdout.String(), `unicodenames.Gr\u00f6\u00dfe and ptrcomp/testdata/src/unicodenames.Gr\u00f6\u00dfe (in vergleiche)`+"\n"), stdout.String())
+
+	escaped := escapeFindings([]finding{{Finding: report.Finding{Message: "bad \xff byte, emoji \U0001F600", Left: "*p.Größe"}}}, true)
+	assert.Equal(t, `bad \ufffd byte, emoji \U0001f600`, escaped[0].Message)
+	assert.Equal(t, `*p.Gr\u00f6\u00dfe`, escaped[0].Left)
+	escaped = escapeFindings([]finding{{Finding: report.Finding{Message: "bad \xff byte, Größe"}}}, false)
+	assert.Equal(t, "bad \uFFFD byte, Größe", escaped[0].Message)
+}
+
+func TestPathStyle(t *testing.T) {
+	name := filepath.Join("dir", "sub", "a.go")
+	findings := posixPaths([]finding{{Finding: report.Finding{Pos: token.Position{Filename: name, Line: 1, Column: 1}}}})
+	assert.Equal(t, "dir/sub/a.go", findings[0].Pos.Filename)
+
+	var stdout, stderr strings.Builder
+	code := run([]string{"-path-style", "posix", "-rel-to", "cwd", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(stdout.String(), "testdata/src/multipkg/first/first.go:22:6: "), stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"-rel-to", "cwd", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(stdout.String(), filepath.Join("testdata", "src", "multipkg", "first", "first.go")+":22:6: "), stdout.String())
+
+	code = run([]string{"-path-style", "windows", "./testdata/src/multipkg"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+}
@@ -0,0 +1,63 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressLine rewrites a single "analyzed X/Y packages" line on a terminal,
+// at most once per interval.
+type progressLine struct {
+	w        io.Writer
+	interval time.Duration
+	last     time.Time
+	written  bool
+}
+
+func newProgressLine(w io.Writer) *progressLine {
+	return &progressLine{w: w, interval: 200 * time.Millisecond}
+}
+
+// update records that done of total packages have been analyzed. The line is
+// redrawn when the interval has passed since the last redraw or done reaches
+// total.
+func (p *progressLine) update(done, total int) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	if done < total && p.written && now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last, p.written = now, true
+	fmt.Fprintf(p.w, "\ranalyzed %d/%d packages", done, total)
+}
+
+// clear erases the line so that whatever is written to the terminal next
+// starts at the beginning of an empty line.
+func (p *progressLine) clear() {
+	if p == nil || !p.written {
+		return
+	}
+	p.written = false
+	fmt.Fprint(p.w, "\r\x1b[K")
+}
@@ -0,0 +1,104 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package report
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+)
+
+// JSON writes all findings as a single JSON array when flushed.
+type JSON struct {
+	w        io.Writer
+	findings []jsonFinding
+}
+
+type jsonFinding struct {
+	File     string         `json:"file"`
+	Line     int            `json:"line"`
+	Column   int            `json:"column"`
+	Message  string         `json:"message"`
+	Rule     string         `json:"rule"`
+	Category string         `json:"category"`
+	Severity string         `json:"severity"`
+	Function string         `json:"function,omitempty"`
+	Related  []jsonLocation `json:"related,omitempty"`
+}
+
+type jsonLocation struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// NewJSON returns a JSON reporter writing to w.
+func NewJSON(w io.Writer) *JSON {
+	return &JSON{w: w, findings: make([]jsonFinding, 0)}
+}
+
+func (j *JSON) Report(f Finding) {
+	var related []jsonLocation
+	for _, r := range f.Related {
+		related = append(related, jsonLocation{File: r.Pos.Filename, Line: r.Pos.Line, Column: r.Pos.Column, Message: r.Message})
+	}
+	j.findings = append(j.findings, jsonFinding{
+		File:     f.Pos.Filename,
+		Line:     f.Pos.Line,
+		Column:   f.Pos.Column,
+		Message:  f.Message,
+		Rule:     f.Rule,
+		Category: f.Category,
+		Severity: f.Severity,
+		Function: f.Function,
+		Related:  related,
+	})
+}
+
+func (j *JSON) Flush() error {
+	encoder := json.NewEncoder(j.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(j.findings)
+}
+
+// ReadJSON reads findings in the format written by the JSON reporter.
+func ReadJSON(r io.Reader) ([]Finding, error) {
+	var decoded []jsonFinding
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	findings := make([]Finding, 0, len(decoded))
+	for _, f := range decoded {
+		var related []Location
+		for _, r := range f.Related {
+			related = append(related, Location{Pos: token.Position{Filename: r.File, Line: r.Line, Column: r.Column}, Message: r.Message})
+		}
+		findings = append(findings, Finding{
+			Pos:      token.Position{Filename: f.File, Line: f.Line, Column: f.Column},
+			Message:  f.Message,
+			Rule:     f.Rule,
+			Category: f.Category,
+			Severity: f.Severity,
+			Function: f.Function,
+			Related:  related,
+		})
+	}
+	return findings, nil
+}
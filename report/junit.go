@@ -0,0 +1,103 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// JUnit writes all findings as JUnit XML when flushed, for CI systems that
+// show test reports. Each file with findings becomes a test suite and each
+// finding a test case, failed unless it is at info severity.
+type JUnit struct {
+	w      io.Writer
+	suites []*junitSuite
+	byFile map[string]*junitSuite
+}
+
+type junitSuites struct {
+	XMLName  xml.Name      `xml:"testsuites"`
+	Name     string        `xml:"name,attr"`
+	Tests    int           `xml:"tests,attr"`
+	Failures int           `xml:"failures,attr"`
+	Suites   []*junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// NewJUnit returns a JUnit reporter writing to w.
+func NewJUnit(w io.Writer) *JUnit {
+	return &JUnit{w: w, byFile: make(map[string]*junitSuite)}
+}
+
+func (j *JUnit) Report(f Finding) {
+	suite, ok := j.byFile[f.Pos.Filename]
+	if !ok {
+		suite = &junitSuite{Name: f.Pos.Filename}
+		j.byFile[f.Pos.Filename] = suite
+		j.suites = append(j.suites, suite)
+	}
+	c := junitCase{Name: fmt.Sprintf("%d:%d", f.Pos.Line, f.Pos.Column), Classname: f.Pos.Filename}
+	if f.Severity != SeverityError {
+		c.SystemOut = f.String()
+	} else {
+		c.Failure = &junitFailure{Message: f.Message, Type: f.Rule, Text: f.String()}
+		suite.Failures++
+	}
+	suite.Tests++
+	suite.Cases = append(suite.Cases, c)
+}
+
+func (j *JUnit) Flush() error {
+	doc := junitSuites{Name: "ptrcmp", Suites: j.suites}
+	for _, s := range j.suites {
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+	}
+	if _, err := io.WriteString(j.w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(j.w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n")
+	return err
+}
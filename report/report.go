@@ -0,0 +1,87 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+
+// Package report writes ptrcmp findings to an output sink. The command picks
+// one of the built-in reporters with -format; programs running the analyzer
+// themselves can use them too.
+package report
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// Severities a finding can be reported at. Only errors count as failures.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding is a single reported comparison.
+type Finding struct {
+	Pos     token.Position
+	Message string
+	// Rule is the ID of the rule that produced the finding, see analyzer.Rules.
+	Rule string
+	// Category is the kind of file the finding is in: "first-party",
+	// "generated" or "vendored".
+	Category string
+	// Severity is SeverityError, SeverityWarning or SeverityInfo.
+	Severity string
+	// Op is the operator of the comparison, e.g. "==", and Left and Right
+	// are the types of its operands, e.g. "*int". They are empty if the
+	// comparison couldn't be found.
+	Op, Left, Right string
+	// Function is the function containing the finding, named like in
+	// runtime stack traces, e.g. "(*Cache).Get" or "Load.func1" for the
+	// first closure in Load. It is empty outside of functions.
+	Function string
+	// Related are other locations relevant to the finding, e.g. the
+	// declarations of the compared operands.
+	Related []Location
+}
+
+// Location is a position related to a finding, with a message explaining
+// how it's related.
+type Location struct {
+	Pos     token.Position
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.label())
+}
+
+// label returns the message, prefixed with the severity unless it's an error.
+func (f Finding) label() string {
+	if f.Severity == SeverityInfo || f.Severity == SeverityWarning {
+		return f.Severity + ": " + f.Message
+	}
+	return f.Message
+}
+
+// Reporter receives findings in order and writes them out. Report may buffer
+// findings, e.g. to write a single document; Flush writes anything buffered
+// and returns the first error encountered. Flush is called exactly once,
+// after the last finding, even when there were no findings.
+type Reporter interface {
+	Report(Finding)
+	Flush() error
+}
@@ -0,0 +1,197 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"go/token"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+var findings = []Finding{
+	{Pos: token.Position{Filename: "a.go", Line: 3, Column: 5}, Message: "comparing pointers to basic types: int and int", Rule: "same-type", Category: "first-party", Severity: SeverityError, Function: "(*T).Compare",
+		Related: []Location{{Pos: token.Position{Filename: "a.go", Line: 2, Column: 6}, Message: "a declared here"}}},
+	{Pos: token.Position{Filename: "/src/gen.go", Line: 7, Column: 2}, Message: "comparing pointer to basic type with nil: string", Rule: "nil", Category: "generated", Severity: SeverityInfo},
+}
+
+func reportAll(r Reporter) error {
+	for _, f := range findings {
+		r.Report(f)
+	}
+	return r.Flush()
+}
+
+func TestText(t *testing.T) {
+	var out strings.Builder
+	assert.Nil(t, reportAll(NewText(&out, false)))
+	assert.Equal(t, "a.go:3:5: comparing pointers to basic types: int and int\n/src/gen.go:7:2: info: comparing pointer to basic type with nil: string\n", out.String())
+
+	out.Reset()
+	r := NewText(&out, false)
+	r.Function = true
+	assert.Nil(t, reportAll(r))
+	assert.Equal(t, "a.go:3:5: comparing pointers to basic types: int and int (in (*T).Compare)\n/src/gen.go:7:2: info: comparing pointer to basic type with nil: string\n", out.String())
+}
+
+func TestTextContext(t *testing.T) {
+	var out strings.Builder
+	r := NewText(&out, false)
+	r.Context = 2
+	r.ReadFile = func(name string) ([]byte, error) {
+		return []byte("package a\n\nfunc f(p, q *int) bool {\n\treturn p == q\n}\n"), nil
+	}
+	r.Report(Finding{Pos: token.Position{Filename: "a.go", Line: 4, Column: 9}, Message: "comparing pointers to basic types: int and int"})
+	r.Report(Finding{Pos: token.Position{Filename: "a.go", Line: 1, Column: 1}, Message: "first line"})
+	r.Report(Finding{Pos: token.Position{Filename: "a.go", Line: 9, Column: 1}, Message: "past the end"})
+	assert.Nil(t, r.Flush())
+	assert.Equal(t, "a.go:4:9: comparing pointers to basic types: int and int\n"+
+		"  2 | \n"+
+		"  3 | func f(p, q *int) bool {\n"+
+		"> 4 | \treturn p == q\n"+
+		"  5 | }\n"+
+		"a.go:1:1: first line\n"+
+		"> 1 | package a\n"+
+		"  2 | \n"+
+		"  3 | func f(p, q *int) bool {\n"+
+		"a.go:9:1: past the end\n", out.String())
+}
+
+func TestJSON(t *testing.T) {
+	var out strings.Builder
+	assert.Nil(t, reportAll(NewJSON(&out)))
+
+	var decoded []map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(out.String()), &decoded))
+	assert.Equal(t, 2, len(decoded))
+	assert.Equal(t, map[string]any{
+		"file": "a.go", "line": 3.0, "column": 5.0, "message": "comparing pointers to basic types: int and int",
+		"rule": "same-type", "category": "first-party", "severity": "error", "function": "(*T).Compare",
+		"related": []any{map[string]any{"file": "a.go", "line": 2.0, "column": 6.0, "message": "a declared here"}},
+	}, decoded[0])
+	assert.NotContains(t, decoded[1], "function")
+	assert.NotContains(t, decoded[1], "related")
+
+	read, err := ReadJSON(strings.NewReader(out.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, findings, read)
+
+	out.Reset()
+	assert.Nil(t, NewJSON(&out).Flush())
+	assert.Equal(t, "[]\n", out.String())
+}
+
+func TestSARIF(t *testing.T) {
+	var out strings.Builder
+	assert.Nil(t, reportAll(NewSARIF(&out, "v1.2.3")))
+
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal([]byte(out.String()), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Equal(t, 1, len(log.Runs))
+	assert.Equal(t, "v1.2.3", log.Runs[0].Tool.Driver.Version)
+	assert.Equal(t, "same-type", log.Runs[0].Tool.Driver.Rules[0].ID)
+
+	results := log.Runs[0].Results
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, "same-type", results[0].RuleID)
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, "a.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, []sarifLogicalLocation{{Name: "(*T).Compare", Kind: "function"}}, results[0].Locations[0].LogicalLocations)
+	assert.Equal(t, 1, len(results[0].RelatedLocations))
+	assert.Equal(t, 1, results[0].RelatedLocations[0].ID)
+	assert.Equal(t, 2, results[0].RelatedLocations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, &sarifMessage{Text: "a declared here"}, results[0].RelatedLocations[0].Message)
+	assert.Equal(t, 0, len(results[1].Locations[0].LogicalLocations))
+	assert.Equal(t, 0, len(results[1].RelatedLocations))
+	assert.Equal(t, "note", results[1].Level)
+	assert.Equal(t, "file:///src/gen.go", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestJUnit(t *testing.T) {
+	var out strings.Builder
+	r := NewJUnit(&out)
+	r.Report(Finding{Pos: token.Position{Filename: "a.go", Line: 3, Column: 5}, Message: "comparing <pointers> & more", Rule: "same-type", Severity: SeverityError})
+	assert.Nil(t, reportAll(r))
+
+	var doc junitSuites
+	assert.Nil(t, xml.Unmarshal([]byte(out.String()), &doc))
+	assert.Equal(t, 3, doc.Tests)
+	assert.Equal(t, 2, doc.Failures)
+	assert.Equal(t, 2, len(doc.Suites))
+	assert.Equal(t, "a.go", doc.Suites[0].Name)
+	assert.Equal(t, 2, len(doc.Suites[0].Cases))
+	assert.Equal(t, "3:5", doc.Suites[0].Cases[0].Name)
+	assert.Equal(t, "comparing <pointers> & more", doc.Suites[0].Cases[0].Failure.Message)
+	assert.Equal(t, "same-type", doc.Suites[0].Cases[0].Failure.Type)
+	assert.Nil(t, doc.Suites[1].Cases[0].Failure)
+	assert.True(t, strings.Contains(out.String(), "comparing &lt;pointers&gt; &amp; more"))
+
+	out.Reset()
+	assert.Nil(t, NewJUnit(&out).Flush())
+	assert.Nil(t, xml.Unmarshal([]byte(out.String()), &doc))
+	assert.Equal(t, 0, doc.Tests)
+}
+
+func TestWarningSeverity(t *testing.T) {
+	warning := Finding{Pos: token.Position{Filename: "a.go", Line: 3, Column: 5}, Message: "comparing pointers to basic types: int and int", Rule: "same-type", Severity: SeverityWarning}
+	assert.Equal(t, "a.go:3:5: warning: comparing pointers to basic types: int and int", warning.String())
+
+	var out strings.Builder
+	s := NewSARIF(&out, "v1.2.3")
+	s.Report(warning)
+	assert.Nil(t, s.Flush())
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal([]byte(out.String()), &log))
+	assert.Equal(t, "warning", log.Runs[0].Results[0].Level)
+
+	out.Reset()
+	j := NewJUnit(&out)
+	j.Report(warning)
+	assert.Nil(t, j.Flush())
+	var doc junitSuites
+	assert.Nil(t, xml.Unmarshal([]byte(out.String()), &doc))
+	assert.Equal(t, 1, doc.Tests)
+	assert.Equal(t, 0, doc.Failures)
+}
+
+func TestTemplate(t *testing.T) {
+	var out strings.Builder
+	r, err := NewTemplate(&out, template.Must(template.New("").Parse("{{.File}}:{{.Line}}:{{.Col}} {{.RuleID}} {{.Severity}} {{.Left}}{{.Op}}{{.Right}} {{.Function}}\n")))
+	assert.Nil(t, err)
+	f := findings[0]
+	f.Op, f.Left, f.Right = "==", "*int", "*int"
+	r.Report(f)
+	r.Report(findings[1])
+	assert.Nil(t, r.Flush())
+	assert.Equal(t, "a.go:3:5 same-type error *int==*int (*T).Compare\n/src/gen.go:7:2 nil info  \n", out.String())
+
+	_, err = NewTemplate(&out, template.Must(template.New("").Parse("{{.Missing}}\n")))
+	assert.NotNil(t, err)
+
+	out.Reset()
+	r, err = NewTemplate(&out, template.Must(template.New("").Parse(`{{if eq .Line 7}}{{index .File 99}}{{else}}{{.File}}{{end}}`)))
+	assert.Nil(t, err)
+	assert.NotNil(t, reportAll(r))
+	assert.Equal(t, "a.go", out.String())
+}
@@ -0,0 +1,169 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package report
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"path/filepath"
+	"ptrcomp/analyzer"
+)
+
+// SARIF writes all findings as a SARIF 2.1.0 log when flushed, for code
+// scanning tools such as GitHub's.
+type SARIF struct {
+	w       io.Writer
+	version string
+	results []sarifResult
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	// RelatedLocations are numbered from 1 by ID, so messages can link to
+	// them.
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	ID               int `json:"id,omitempty"`
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn,omitempty"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// NewSARIF returns a SARIF reporter writing to w, naming version as the
+// version of the tool.
+func NewSARIF(w io.Writer, version string) *SARIF {
+	return &SARIF{w: w, version: version, results: make([]sarifResult, 0)}
+}
+
+func (s *SARIF) Report(f Finding) {
+	level := "error"
+	switch f.Severity {
+	case SeverityWarning:
+		level = "warning"
+	case SeverityInfo:
+		level = "note"
+	}
+	loc := sarifPhysicalLocation(f.Pos)
+	if f.Function != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{{Name: f.Function, Kind: "function"}}
+	}
+	var related []sarifLocation
+	for i, r := range f.Related {
+		rel := sarifPhysicalLocation(r.Pos)
+		rel.ID = i + 1
+		rel.Message = &sarifMessage{Text: r.Message}
+		related = append(related, rel)
+	}
+	s.results = append(s.results, sarifResult{
+		RuleID:           f.Rule,
+		Level:            level,
+		Message:          sarifMessage{Text: f.Message},
+		Locations:        []sarifLocation{loc},
+		RelatedLocations: related,
+	})
+}
+
+// sarifPhysicalLocation returns a location pointing at pos.
+func sarifPhysicalLocation(pos token.Position) sarifLocation {
+	var loc sarifLocation
+	loc.PhysicalLocation.ArtifactLocation.URI = sarifURI(pos.Filename)
+	loc.PhysicalLocation.Region.StartLine = pos.Line
+	loc.PhysicalLocation.Region.StartColumn = pos.Column
+	return loc
+}
+
+func (s *SARIF) Flush() error {
+	rules := make([]sarifRule, 0, len(analyzer.Rules))
+	for _, r := range analyzer.Rules {
+		rules = append(rules, sarifRule{ID: r.ID, ShortDescription: sarifMessage{Text: r.Doc}})
+	}
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "ptrcmp", Version: s.version, Rules: rules}},
+			Results: s.results,
+		}},
+	}
+	encoder := json.NewEncoder(s.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifURI returns filename as a URI reference: absolute paths become file
+// URIs, relative ones are kept relative with forward slashes.
+func sarifURI(filename string) string {
+	uri := filepath.ToSlash(filename)
+	if filepath.IsAbs(filename) {
+		if uri[0] != '/' {
+			uri = "/" + uri
+		}
+		return "file://" + uri
+	}
+	return uri
+}
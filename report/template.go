@@ -0,0 +1,93 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package report
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"text/template"
+)
+
+// TemplateData is what a Template reporter executes its template with for
+// each finding.
+type TemplateData struct {
+	File     string
+	Line     int
+	Col      int
+	Message  string
+	Op       string
+	Left     string
+	Right    string
+	Function string
+	RuleID   string
+	Category string
+	Severity string
+}
+
+// Template writes each finding by executing a text/template with its
+// TemplateData as soon as it is reported. Nothing is added between findings,
+// so templates usually end with a newline.
+type Template struct {
+	w    io.Writer
+	tmpl *template.Template
+	err  error
+}
+
+// NewTemplate returns a Template reporter writing to w. The template is
+// checked by executing it once with a sample finding, so that mistakes such
+// as unknown fields are reported before any packages are analyzed.
+func NewTemplate(w io.Writer, tmpl *template.Template) (*Template, error) {
+	sample := Finding{Pos: token.Position{Filename: "sample.go", Line: 1, Column: 1}, Message: "sample", Rule: "same-type", Category: "first-party", Severity: SeverityError, Op: "==", Left: "*int", Right: "*int"}
+	if err := tmpl.Execute(io.Discard, templateData(sample)); err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return &Template{w: w, tmpl: tmpl}, nil
+}
+
+func templateData(f Finding) TemplateData {
+	return TemplateData{
+		File:     f.Pos.Filename,
+		Line:     f.Pos.Line,
+		Col:      f.Pos.Column,
+		Message:  f.Message,
+		Op:       f.Op,
+		Left:     f.Left,
+		Right:    f.Right,
+		Function: f.Function,
+		RuleID:   f.Rule,
+		Category: f.Category,
+		Severity: f.Severity,
+	}
+}
+
+// Report executes the template for f. After the first failure nothing more
+// is written, and Flush returns the error.
+func (t *Template) Report(f Finding) {
+	if t.err != nil {
+		return
+	}
+	if err := t.tmpl.Execute(t.w, templateData(f)); err != nil {
+		t.err = fmt.Errorf("executing template for %s: %v", f.Pos, err)
+	}
+}
+
+func (t *Template) Flush() error {
+	return t.err
+}
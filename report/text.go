@@ -0,0 +1,116 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape codes the Text reporter colorizes findings with, also used by
+// the command's other colorized output.
+const (
+	ANSIBold   = "\x1b[1m"
+	ANSIYellow = "\x1b[33m"
+	ANSIReset  = "\x1b[0m"
+)
+
+// Text writes each finding as a "file:line:col: message" line as soon as it
+// is reported, optionally colorized with ANSI escape codes.
+type Text struct {
+	// Context is the number of source lines to print before and after the
+	// line of each finding, like grep -C. The finding's line is marked with
+	// ">". Zero prints no source.
+	Context int
+	// ReadFile reads the source printed for Context. It defaults to
+	// os.ReadFile.
+	ReadFile func(name string) ([]byte, error)
+	// Function appends the function containing each finding to its line,
+	// e.g. " (in (*Cache).Get)".
+	Function bool
+
+	w       io.Writer
+	color   bool
+	err     error
+	sources map[string][]string
+}
+
+// NewText returns a Text reporter writing to w.
+func NewText(w io.Writer, color bool) *Text {
+	return &Text{w: w, color: color}
+}
+
+func (t *Text) Report(f Finding) {
+	if t.err != nil {
+		return
+	}
+	var in string
+	if t.Function && f.Function != "" {
+		in = " (in " + f.Function + ")"
+	}
+	if t.color {
+		_, t.err = fmt.Fprintf(t.w, "%s%s%s: %s%s%s%s\n", ANSIBold, f.Pos, ANSIReset, ANSIYellow, f.label(), ANSIReset, in)
+	} else {
+		_, t.err = fmt.Fprintf(t.w, "%s%s\n", f, in)
+	}
+	if t.Context > 0 && t.err == nil {
+		t.err = t.writeContext(f.Pos.Filename, f.Pos.Line)
+	}
+}
+
+// writeContext prints the lines around line of the named file, clamped to
+// the start and end of the file. Files that can't be read print nothing.
+func (t *Text) writeContext(name string, line int) error {
+	lines, ok := t.sources[name]
+	if !ok {
+		readFile := t.ReadFile
+		if readFile == nil {
+			readFile = os.ReadFile
+		}
+		if src, err := readFile(name); err == nil {
+			lines = strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+		}
+		if t.sources == nil {
+			t.sources = make(map[string][]string)
+		}
+		t.sources[name] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	first, last := max(line-t.Context, 1), min(line+t.Context, len(lines))
+	width := len(fmt.Sprint(last))
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		if _, err := fmt.Fprintf(t.w, "%s %*d | %s\n", marker, width, n, lines[n-1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Text) Flush() error {
+	return t.err
+}
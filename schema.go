@@ -0,0 +1,92 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"strconv"
+	"time"
+)
+
+// flagChoices are the values accepted by string flags that run validates
+// after parsing. Flags of the analyzer list their own with a Choices method.
+var flagChoices = map[string][]string{
+	"color":      {"auto", "always", "never"},
+	"format":     {"text", "json", "sarif", "junit", "template"},
+	"path-style": {"native", "posix"},
+	"rel-to":     {"cwd", "module"},
+}
+
+// flagSchema describes a flag for -config-schema.
+type flagSchema struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     any      `json:"default"`
+	Description string   `json:"description"`
+	Values      []string `json:"values,omitempty"`
+}
+
+// writeConfigSchema writes a JSON description of every flag registered on fs
+// to w: its name, type (bool, int, duration, string or list, for comma
+// separated values), default, description and, if only some are allowed, the
+// values it accepts, or for lists the values of their items.
+func writeConfigSchema(w io.Writer, fs *flag.FlagSet) error {
+	schema := struct {
+		Flags []flagSchema `json:"flags"`
+	}{Flags: make([]flagSchema, 0)}
+	fs.VisitAll(func(f *flag.Flag) {
+		typ := flagType(f.Value)
+		var def any = f.DefValue
+		switch typ {
+		case "bool":
+			def, _ = strconv.ParseBool(f.DefValue)
+		case "int":
+			def, _ = strconv.Atoi(f.DefValue)
+		}
+		values := flagChoices[f.Name]
+		if c, ok := f.Value.(interface{ Choices() []string }); ok {
+			values = c.Choices()
+		}
+		schema.Flags = append(schema.Flags, flagSchema{Name: f.Name, Type: typ, Default: def, Description: f.Usage, Values: values})
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
+
+// flagType returns the type of a flag's value as described by -config-schema.
+func flagType(v flag.Value) string {
+	if t, ok := v.(interface{ Type() string }); ok {
+		return t.Type()
+	}
+	if b, ok := v.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return "bool"
+	}
+	if g, ok := v.(flag.Getter); ok {
+		switch g.Get().(type) {
+		case int:
+			return "int"
+		case time.Duration:
+			return "duration"
+		}
+	}
+	return "string"
+}
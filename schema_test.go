@@ -0,0 +1,53 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestConfigSchema(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := run([]string{"-config-schema"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+
+	var schema struct {
+		Flags []flagSchema `json:"flags"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(stdout.String()), &schema))
+	flags := make(map[string]flagSchema)
+	for _, f := range schema.Flags {
+		flags[f.Name] = f
+	}
+	assert.Equal(t, flagSchema{Name: "stats", Type: "bool", Default: false, Description: "write a JSON summary of the run to stderr"}, flags["stats"])
+	assert.Equal(t, "int", flags["limit"].Type)
+	assert.Equal(t, float64(0), flags["limit"].Default)
+	assert.Equal(t, "duration", flags["timeout"].Type)
+	assert.Equal(t, "list", flags["kinds"].Type)
+	assert.Equal(t, []string{"deref-both", "none", "comment"}, flags["fix-mode"].Values)
+	assert.Contains(t, flags["rules"].Values, "same-type")
+	assert.Contains(t, flags, "config-schema")
+
+	for name, values := range flagChoices {
+		assert.Equal(t, values, flags[name].Values, name)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// version is the tool version reported by -stats. Release builds set it with
+// -ldflags "-X main.version=v1.2.3"; otherwise the module version is used.
+var version = "dev"
+
+func toolVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
+// runStats summarizes a run for -stats.
+type runStats struct {
+	Version         string         `json:"version"`
+	Packages        int            `json:"packages"`
+	Files           int            `json:"files"`
+	Findings        int            `json:"findings"`
+	FindingsByRule  map[string]int `json:"findings_by_rule"`
+	DurationSeconds float64        `json:"duration_seconds"`
+}
+
+func newRunStats() runStats {
+	return runStats{Version: toolVersion(), FindingsByRule: make(map[string]int)}
+}
+
+// finish records the findings and duration of the run.
+func (s *runStats) finish(findings []finding, duration time.Duration) {
+	s.Findings = len(findings)
+	for _, f := range findings {
+		s.FindingsByRule[f.Rule]++
+	}
+	s.DurationSeconds = duration.Seconds()
+}
+
+func writeStats(w io.Writer, stats runStats) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(stats)
+}
+
+// writeMetrics writes stats in the Prometheus text exposition format, for
+// node_exporter's textfile collector. Every value describes the last run
+// only, so they are gauges.
+func writeMetrics(w io.Writer, stats runStats) error {
+	metrics := []struct {
+		name, help string
+		value      float64
+	}{
+		{"ptrcmp_findings_total", "Findings reported by the last ptrcmp run.", float64(stats.Findings)},
+		{"ptrcmp_packages_analyzed_total", "Packages analyzed by the last ptrcmp run.", float64(stats.Packages)},
+		{"ptrcmp_duration_seconds", "Time taken by the last ptrcmp run to load and analyze packages.", stats.DurationSeconds},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %v\n", m.name, m.help, m.name, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMetricsFile writes the metrics for stats to name atomically: they are
+// written to a temporary file in the same directory, which is then renamed,
+// so the textfile collector never reads a partially written file.
+func writeMetricsFile(name string, stats runStats) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeMetrics(tmp, stats); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp makes files only readable by their owner, while the
+	// collector usually runs as another user.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
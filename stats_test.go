@@ -0,0 +1,83 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"ptrcomp/analyzer"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	findings, stats, err := analyzeDir(context.Background(), "./testdata/src/options", analyzer.Options{FlagNil: true}, driverConfig{}, nil)
+	assert.Nil(t, err)
+	stats.finish(findings, 1500*time.Millisecond)
+
+	var out strings.Builder
+	err = writeStats(&out, stats)
+	assert.Nil(t, err)
+
+	var decoded map[string]any
+	err = json.Unmarshal([]byte(out.String()), &decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, "dev", decoded["version"])
+	assert.Equal(t, float64(1), decoded["packages"])
+	assert.Equal(t, float64(1), decoded["files"])
+	assert.Equal(t, float64(5), decoded["findings"])
+	assert.Equal(t, map[string]any{"same-type": float64(3), "nil": float64(2)}, decoded["findings_by_rule"])
+	assert.Equal(t, 1.5, decoded["duration_seconds"])
+}
+
+func TestMetrics(t *testing.T) {
+	var out strings.Builder
+	assert.Nil(t, writeMetrics(&out, runStats{Packages: 3, Findings: 5, DurationSeconds: 1.5}))
+	assert.Equal(t, "# HELP ptrcmp_findings_total Findings reported by the last ptrcmp run.\n"+
+		"# TYPE ptrcmp_findings_total gauge\n"+
+		"ptrcmp_findings_total 5\n"+
+		"# HELP ptrcmp_packages_analyzed_total Packages analyzed by the last ptrcmp run.\n"+
+		"# TYPE ptrcmp_packages_analyzed_total gauge\n"+
+		"ptrcmp_packages_analyzed_total 3\n"+
+		"# HELP ptrcmp_duration_seconds Time taken by the last ptrcmp run to load and analyze packages.\n"+
+		"# TYPE ptrcmp_duration_seconds gauge\n"+
+		"ptrcmp_duration_seconds 1.5\n", out.String())
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "ptrcmp.prom")
+	assert.Nil(t, os.WriteFile(name, []byte("stale"), 0o644))
+	var stdout, stderr strings.Builder
+	code := run([]string{"-metrics-file", name, "-quiet-clean", "-kinds", "complex128", "./testdata/src/options"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout.String())
+	data, err := os.ReadFile(name)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(data), "\nptrcmp_findings_total 0\n"))
+	assert.True(t, strings.Contains(string(data), "\nptrcmp_packages_analyzed_total 1\n"))
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+
+	code = run([]string{"-metrics-file", filepath.Join(dir, "missing", "ptrcmp.prom"), "./testdata/src/options"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+}
@@ -0,0 +1,3 @@
+module crosspkg
+
+go 1.23
@@ -0,0 +1,66 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package addressof
+
+type T struct {
+	N    int
+	Name string
+}
+
+func addresses(a [4]int, s []int, t *T, x, y int) {
+	p1 := &a[0]
+	p2 := &a[1]
+	_ = p1 == p2                     // want `comparing pointers to basic types: int and int`
+	_ = &x == &y                     // want `comparing pointers to basic types: int and int`
+	_ = &s[0] == p1                  // want `comparing pointers to basic types: int and int`
+	_ = &t.N != &a[2]                // want `comparing pointers to basic types: int and int`
+	_ = &[]string{"a"}[0] == &t.Name // want `comparing pointers to basic types: string and string`
+	_ = &T{} == &T{}
+	_ = &a == &[4]int{}
+}
+
+type Counter struct{ N int }
+
+func (c *Counter) Addr() *int { return &c.N }
+
+// The addresses of the same field of the same object are always equal, so
+// unlike comparisons against new the messages must not claim a result.
+func fields(a, b *Counter, v Counter) {
+	_ = &a.N == &b.N // want `comparing pointers to basic types: int and int$`
+	_ = &a.N == &a.N // want `comparing addresses of the same variable: a.N; always true$`
+	_ = &a.N != &a.N // want `comparing addresses of the same variable: a.N; always false$`
+	_ = &v.N != &a.N // want `comparing pointers to basic types: int and int$`
+	addr := a.Addr
+	_ = addr() == b.Addr()         // want `comparing pointers to basic types: int and int$`
+	_ = (*Counter).Addr(a) == &b.N // want `comparing pointers to basic types: int and int$`
+}
+
+// Elements of arrays, including arrays behind pointers and in other arrays,
+// are addressable, unlike map elements, which can only be compared as values
+// or through pointers stored in the map.
+func elements(arr [4]int, parr *[4]int, grid [2][2]int, m map[string]*int, mv map[string][2]int, k string, i, j int) {
+	_ = &arr[i] == &arr[j]         // want `comparing pointers to basic types: int and int`
+	_ = &parr[i] != &arr[j]        // want `comparing pointers to basic types: int and int`
+	_ = &grid[i][j] == &grid[j][i] // want `comparing pointers to basic types: int and int`
+	_ = &*m[k] == m[k]             // want `comparing pointers to basic types: int and int`
+	v := mv[k]
+	_ = &v[0] == &v[1] // want `comparing pointers to basic types: int and int`
+	_ = mv[k][0] == mv[k][1]
+	_ = &grid[0] == &grid[1]
+}
@@ -0,0 +1,34 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package aliases
+
+type Celsius = float64
+
+type Reading = *Celsius
+
+type Kelvin float64
+
+func compare(a *Celsius, b *float64, r Reading, k *Kelvin) {
+	_ = a == b // want `comparing pointers to basic types: aliases\.Celsius and float64`
+	_ = b != a // want `comparing pointers to basic types: float64 and aliases\.Celsius`
+	_ = r == b // want `comparing pointers to basic types: aliases\.Celsius and float64`
+	_ = a == r // want `comparing pointers to basic types: aliases\.Celsius and aliases\.Celsius`
+	_ = r == nil
+	_ = *r == *b
+}
@@ -0,0 +1,33 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package arrays
+
+func elements(arr [4]*int, grid [3][3]*string, s []*int, m map[string]*int, i, j int) {
+	_ = arr[0] == arr[1]         // want `comparing pointers to basic types: int and int`
+	_ = arr[i] != s[j]           // want `comparing pointers to basic types: int and int`
+	_ = grid[i][j] == grid[j][i] // want `comparing pointers to basic types: string and string`
+	_ = (&arr)[0] == m["key"]    // want `comparing pointers to basic types: int and int`
+	_ = *arr[0] == *arr[1]
+	_ = *grid[0][1] == *grid[1][0]
+	_ = arr == [4]*int{}
+}
+
+func nested(cube [2][2][2]*float64) bool {
+	return cube[0][1][0] == cube[1][0][1] // want `comparing pointers to basic types: float64 and float64`
+}
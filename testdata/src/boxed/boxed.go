@@ -0,0 +1,57 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package boxed
+
+var global any = new(int)
+
+type Stringer interface{ String() string }
+
+func compare(p, q *int, s *string, x, y any) {
+	var i1, i2 interface{} = p, q
+	_ = i1 == i2 // want `comparing interfaces holding pointers to basic types: int and int`
+
+	_ = any(p) != any(s) // want `comparing interfaces holding pointers to basic types: int and string`
+	_ = i1 == p          // want `comparing interfaces holding pointers to basic types: int and int`
+	_ = q == i2          // want `comparing interfaces holding pointers to basic types: int and int`
+
+	boxed := any(s)
+	_ = boxed == any(s) // want `comparing interfaces holding pointers to basic types: string and string`
+
+	// Parameters, package level variables and values of other types may hold
+	// anything.
+	_ = x == y
+	_ = x == i1
+	_ = global == i1
+	n := 1
+	var v any = n
+	_ = v == i1
+
+	// Variables given another value are not followed.
+	reassigned := any(p)
+	reassigned = x
+	_ = reassigned == i1
+	addressed := any(p)
+	mutate(&addressed)
+	_ = addressed == i1
+	var later any
+	later = p
+	_ = later == i1
+}
+
+func mutate(v *any) {}
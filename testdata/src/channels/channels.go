@@ -0,0 +1,44 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package channels
+
+func receive(ch1, ch2 chan *int, names <-chan *string, values chan int) {
+	p := <-ch1
+	q := <-ch2
+	_ = p == q                        // want `comparing pointers to basic types: int and int`
+	_ = <-ch1 == <-ch2                // want `comparing pointers to basic types: int and int`
+	_ = <-ch1 != <-ch1                // want `comparing pointers to basic types: int and int`
+	_ = (<-ch1) == p                  // want `comparing pointers to basic types: int and int`
+	_ = <-names == <-names            // want `comparing pointers to basic types: string and string`
+	if r, ok := <-ch1; ok && r == q { // want `comparing pointers to basic types: int and int`
+		return
+	}
+	select {
+	case r := <-ch2:
+		_ = r != p // want `comparing pointers to basic types: int and int`
+	case s := <-names:
+		_ = s == nil
+	}
+	for r := range ch1 {
+		_ = r == p // want `comparing pointers to basic types: int and int`
+	}
+	_ = *<-ch1 == *<-ch2
+	_ = <-values == <-values
+	_ = ch1 == ch2
+}
@@ -0,0 +1,41 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package commaok
+
+func lookups(m map[string]*int, k1, k2 string, ch chan *string, x any) {
+	v1, _ := m[k1]
+	v2, ok := m[k2]
+	_ = v1 == v2       // want `comparing pointers to basic types: int and int`
+	_ = ok && v1 != v2 // want `comparing pointers to basic types: int and int`
+
+	var v3 *int
+	v3, ok = m[k1]
+	_ = v3 == v1 // want `comparing pointers to basic types: int and int`
+
+	if v4, ok := m[k1]; ok && v4 == v2 { // want `comparing pointers to basic types: int and int`
+	}
+
+	s1, _ := <-ch
+	s2, _ := x.(*string)
+	_ = s1 == s2 // want `comparing pointers to basic types: string and string`
+
+	i1, _ := x.(int)
+	i2, _ := x.(int)
+	_ = i1 == i2
+}
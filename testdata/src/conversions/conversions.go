@@ -0,0 +1,58 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package conversions
+
+import "unsafe"
+
+type Celsius float64
+
+type IntPtr *int
+
+func convert(p *int, u unsafe.Pointer, c *Celsius, f *float64, ip IntPtr) {
+	_ = p == (*int)(u)                         // want `comparing pointers to basic types: int and int`
+	_ = (*int)(u) != p                         // want `comparing pointers to basic types: int and int`
+	_ = (*int)(u) == (*int)(unsafe.Pointer(f)) // want `comparing pointers to basic types: int and int`
+	_ = f == (*float64)(c)                     // want `comparing pointers to basic types: float64 and float64`
+	_ = (*Celsius)(f) == c                     // want `comparing pointers to basic types: conversions\.Celsius and conversions\.Celsius`
+	_ = p == (*int)(ip)                        // want `comparing pointers to basic types: int and int`
+	_ = u == unsafe.Pointer(p)
+	_ = p == (*int)(nil)
+	_ = (*Celsius)(nil) != c
+	_ = ip == IntPtr(nil)
+	_ = p == (*int)(unsafe.Pointer(nil))
+	_ = *p == *(*int)(u)
+	_ = (*int)(unsafe.Add(u, 8)) == p
+	_ = p != (*int)(unsafe.Pointer(uintptr(u)+unsafe.Sizeof(*p)))
+}
+
+// Converting a pointer still compares pointers, while converting the value
+// behind one compares values.
+func wrappers(p, q *int, f *float64, c *Celsius) {
+	_ = p == (*int)(q)                 // want `comparing pointers to basic types: int and int`
+	_ = (*int)(p) != (*int)((q))       // want `comparing pointers to basic types: int and int`
+	_ = (*float64)(c) == f             // want `comparing pointers to basic types: float64 and float64`
+	_ = (*Celsius)((*float64)(c)) != c // want `comparing pointers to basic types: conversions\.Celsius and conversions\.Celsius`
+	_ = *p == int(*q)
+	_ = int64(*p) != int64(*q)
+	_ = float64(*p) == *f
+	_ = Celsius(*f) == *c
+	_ = -*p == *q
+	_ = uint(-*p) == uint(*q)
+	_ = *(*int)(q) == *p
+}
@@ -0,0 +1,33 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package deferred
+
+func cleanup(same bool) {}
+
+func process(same bool) {}
+
+func deferred(p1, p2 *int) {
+	defer cleanup(p1 == p2)              // want `comparing pointers to basic types: int and int`
+	defer func() { cleanup(p1 != p2) }() // want `comparing pointers to basic types: int and int`
+}
+
+func goroutine(a, b *string) {
+	go process(a == b)            // want `comparing pointers to basic types: string and string`
+	go func(same bool) {}(a != b) // want `comparing pointers to basic types: string and string`
+}
@@ -0,0 +1,42 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package diagcalls
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+func report(p, q *int, s, t *string, err error) {
+	if err != nil {
+		panic(p == q) // want `comparing pointers to basic types: int and int`
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("recovered %v: %v", r, s != t) // want `comparing pointers to basic types: string and string`
+		}
+	}()
+	log.Fatalf("%v", p == q)                                       // want `comparing pointers to basic types: int and int`
+	log.Println(fmt.Sprint(errors.New(fmt.Sprintf("%t", s == t)))) // want `comparing pointers to basic types: string and string`
+	panic(fmt.Errorf("mismatch %v %v",
+		p != nil,
+		func() bool { return p == q }(), // want `comparing pointers to basic types: int and int`
+	))
+}
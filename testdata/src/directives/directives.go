@@ -0,0 +1,54 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package directives
+
+func blocks(a, b *int) {
+	_ = a == b // want `comparing pointers to basic types: int and int`
+
+	//ptrcmp:disable identity is intended in this section
+	_ = a == b
+	_ = a != b
+	//ptrcmp:enable
+
+	_ = a == b // want `comparing pointers to basic types: int and int`
+}
+
+func unbalanced(a, b *int) {
+	//ptrcmp:enable without a disable is a no-op
+	_ = a == b // want `comparing pointers to basic types: int and int`
+
+	//ptrcmp:disable
+	_ = a == b
+	//ptrcmp:disable again has no extra effect
+	_ = a != b
+	//ptrcmp:enable
+	_ = a == b // want `comparing pointers to basic types: int and int`
+	//ptrcmp:enable
+	_ = a != b // want `comparing pointers to basic types: int and int`
+
+	// ptrcmp:disable with a space isn't a directive
+	_ = a == b // want `comparing pointers to basic types: int and int`
+	//ptrcmp:disabled isn't either
+	_ = a == b // want `comparing pointers to basic types: int and int`
+}
+
+func toEnd(a, b *int) {
+	//ptrcmp:disable until the end of the file
+	_ = a == b
+}
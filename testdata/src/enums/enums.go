@@ -0,0 +1,52 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package enums
+
+type Status int
+
+const (
+	Pending Status = iota
+	Active
+	Closed
+)
+
+func (s Status) String() string {
+	return [...]string{"pending", "active", "closed"}[s]
+}
+
+type Machine struct {
+	current *Status
+}
+
+func (m *Machine) transition(next *Status) bool {
+	if m.current == next { // want `comparing pointers to basic types: enums\.Status and enums\.Status`
+		return false
+	}
+	m.current = next
+	return true
+}
+
+func isActive(s *Status) bool {
+	active := Active
+	return s == &active // want `comparing pointers to basic types: enums\.Status and enums\.Status`
+}
+
+func sameValue(a, b *Status) bool {
+	return *a == *b && *a == Closed
+}
@@ -0,0 +1,34 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package explainfix
+
+type T struct{ N *int }
+
+type C struct{ N int }
+
+func get() *int { return nil }
+
+func compare(one, two *int, t T, s []*string, c C) {
+	_ = one == two     // want `comparing pointers to basic types: int and int; consider: \*one == \*two$`
+	_ = t.N != get()   // want `comparing pointers to basic types: int and int; consider: \*t\.N != \*get\(\)$`
+	_ = s[0] == s[1]   // want `comparing pointers to basic types: string and string; consider: \*s\[0\] == \*s\[1\]$`
+	_ = (one) == &*two // want `comparing pointers to basic types: int and int; consider: \*one == \*two$`
+	_ = &c.N == &c.N   // want `comparing addresses of the same variable: c.N; always true$`
+	_ = one == nil
+}
@@ -0,0 +1,57 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package exportedonly
+
+var global, other *int
+
+var initialized = global == other
+
+type Cache struct{ p *int }
+
+func Equal(a, b *int) bool {
+	return a == b // want `comparing pointers to basic types: int and int`
+}
+
+func equal(a, b *int) bool {
+	return a == b
+}
+
+func (c *Cache) Has(p *int) bool {
+	return c.p == p // want `comparing pointers to basic types: int and int`
+}
+
+func (c *Cache) has(p *int) bool {
+	return c.p == p
+}
+
+func Find(ps []*int, target *int) bool {
+	match := func(p *int) bool {
+		return p == target // want `comparing pointers to basic types: int and int`
+	}
+	for _, p := range ps {
+		if match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func find(s, t *string) func() bool {
+	return func() bool { return s != t }
+}
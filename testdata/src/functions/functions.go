@@ -0,0 +1,53 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package functions
+
+type Cache struct{ hits *int }
+
+type List[T any] struct{ head *int }
+
+var compare = func(a, b *int) bool { return a == b } // want `comparing pointers to basic types: int and int`
+
+func Top(a, b *int) bool {
+	return a == b // want `comparing pointers to basic types: int and int`
+}
+
+func (c *Cache) Get(n *int) bool {
+	return c.hits == n // want `comparing pointers to basic types: int and int`
+}
+
+func (c Cache) Peek(n *int) bool {
+	return c.hits != n // want `comparing pointers to basic types: int and int`
+}
+
+func (l *List[T]) Has(n *int) bool {
+	return l.head == n // want `comparing pointers to basic types: int and int`
+}
+
+func Closures(a, b *int) {
+	first := func() bool { return false }
+	second := func() bool {
+		nested := func() bool { return a == b } // want `comparing pointers to basic types: int and int`
+		return nested()
+	}
+	go func() {
+		_ = a != b // want `comparing pointers to basic types: int and int`
+	}()
+	_, _ = first, second
+}
@@ -0,0 +1,61 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package genericmethods
+
+type Box[T any] struct{ v *T }
+
+func (b *Box[T]) Get() *T { return b.v }
+
+func (b Box[T]) Value() T { return *b.v }
+
+type Pair[K comparable, V any] struct {
+	k *K
+	v *V
+}
+
+func (p Pair[K, V]) Key() *K  { return p.k }
+func (p Pair[K, V]) Elem() *V { return p.v }
+
+type Getter[T any] interface {
+	Get() *T
+}
+
+type Record struct{ N int }
+
+func compare(box, other *Box[int], names Box[string], pair Pair[string, float64], g Getter[int], records *Box[Record]) {
+	_ = box.Get() == other.Get()   // want `comparing pointers to basic types: int and int`
+	_ = names.Get() != names.Get() // want `comparing pointers to basic types: string and string`
+	_ = box.Value() == other.Value()
+	_ = pair.Key() == pair.Key()    // want `comparing pointers to basic types: string and string`
+	_ = pair.Elem() == new(float64) // want `comparing against a freshly allocated pointer to float64 from new; always false`
+	_ = g.Get() == box.Get()        // want `comparing pointers to basic types: int and int`
+	_ = records.Get() == records.Get()
+	_ = (&Box[int]{}).Get() == box.v // want `comparing pointers to basic types: int and int`
+	get := box.Get
+	_ = get() == (*Box[int]).Get(other) // want `comparing pointers to basic types: int and int`
+}
+
+// Inside the generic type the result is still *T.
+func (b *Box[T]) Same(other *Box[T]) bool {
+	return b.Get() == other.Get()
+}
+
+func Same[T ~int](a, b *Box[T]) bool {
+	return a.Get() == b.Get() // want `comparing pointers to basic types: T and T`
+}
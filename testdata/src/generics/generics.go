@@ -0,0 +1,72 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package generics
+
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+type Celsius float64
+
+func Eq[T any](a, b *T) bool {
+	return a == b
+}
+
+func EqComparable[T comparable](a, b *T) bool {
+	return a == b
+}
+
+func EqBasic[T int | string](a, b *T) bool {
+	return a == b // want `comparing pointers to basic types: T and T`
+}
+
+func EqNumber[T Number](a, b *T) bool {
+	return a != b // want `comparing pointers to basic types: T and T`
+}
+
+type Pair struct{ A, B int }
+
+func EqMixed[T int | Pair](a, b *T) bool {
+	return a == b
+}
+
+func callers() {
+	x, y := 1, 2
+	c, d := Celsius(1), Celsius(2)
+	_ = Eq(&x, &y)
+	_ = EqBasic(&x, &y)
+	_ = EqNumber(&c, &d)
+}
+
+func Fresh[T Number](p *T) {
+	a := new(T)
+	b := new(T)
+	_ = a == b             // want `comparing against a freshly allocated pointer to T from new; always false`
+	_ = p == new(T)        // want `comparing against a freshly allocated pointer to T from new; always false`
+	_ = new(T) != (new(T)) // want `comparing against a freshly allocated pointer to T from new; always true`
+	_ = *new(T) == *p
+}
+
+func FreshAny[T any](p *T) {
+	_ = p == new(T)
+}
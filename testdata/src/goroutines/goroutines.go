@@ -0,0 +1,55 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package goroutines
+
+import "sync"
+
+type Pool struct{ target *int }
+
+func spawn(ptrs []*int, target *int) {
+	var wg sync.WaitGroup
+	for _, p := range ptrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if p == target { // want `comparing pointers to basic types: int and int`
+				return
+			}
+		}()
+	}
+	for i := range ptrs {
+		go func(q *int) {
+			_ = q != ptrs[i] // want `comparing pointers to basic types: int and int`
+			go func() {
+				_ = q == target // want `comparing pointers to basic types: int and int`
+			}()
+		}(ptrs[i])
+	}
+	wg.Wait()
+}
+
+func (pool *Pool) Start(ptrs []*int) {
+	for _, p := range ptrs {
+		go func() { _ = p == pool.target }() // want `comparing pointers to basic types: int and int`
+	}
+}
+
+var worker = func(p, q *int) {
+	go func() { _ = p == q }() // want `comparing pointers to basic types: int and int`
+}
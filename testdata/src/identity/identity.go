@@ -0,0 +1,43 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package identity
+
+// Entry is compared by identity: entries are interned, so two pointers to
+// equal entries are the same entry.
+type Entry struct {
+	Key   string
+	Value int
+}
+
+type Token string
+
+type Other struct {
+	Key string
+}
+
+func compare(a, b *Entry, s, t *Token, o, p *Other, n, m *int) {
+	_ = *a == *b   // want `comparing the values behind pointers to identity type identity\.Entry; did you mean to compare the pointers\?`
+	_ = (*a) != *b // want `comparing the values behind pointers to identity type identity\.Entry; did you mean to compare the pointers\?`
+	_ = *s == *t   // want `comparing the values behind pointers to identity type identity\.Token; did you mean to compare the pointers\?`
+	_ = a == b
+	_ = *o == *p
+	_ = *n == *m
+	_ = *a == Entry{}
+	_ = a.Key == b.Key
+}
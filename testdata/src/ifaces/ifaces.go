@@ -0,0 +1,35 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package ifaces
+
+type Getter interface {
+	Get() *int
+}
+
+type Named interface {
+	Getter
+	Name() *string
+}
+
+func compare(a, b Getter, c, d Named) {
+	_ = a.Get() == b.Get()   // want `comparing pointers to basic types: int and int`
+	_ = c.Get() != d.Get()   // want `comparing pointers to basic types: int and int`
+	_ = c.Name() == d.Name() // want `comparing pointers to basic types: string and string`
+	_ = *a.Get() == *b.Get()
+}
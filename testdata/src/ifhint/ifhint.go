@@ -0,0 +1,52 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package ifhint
+
+func constantBranches(one, two *int) int {
+	var x int
+	if one == two { // want `comparing pointers to basic types: int and int \(if/else branches differ only by a constant; did you mean to compare the values\?\)`
+		x = 1
+	} else {
+		x = 2
+	}
+	return x
+}
+
+func constantReturns(one, two *int) bool {
+	if one != two { // want `comparing pointers to basic types: int and int \(if/else branches differ only by a constant; did you mean to compare the values\?\)`
+		return true
+	} else {
+		return false
+	}
+}
+
+func otherBranches(one, two *int) int {
+	if one == two { // want `comparing pointers to basic types: int and int$`
+		return *one
+	}
+	return 0
+}
+
+var closure = func(one, two *int) string {
+	if one == two { // want `comparing pointers to basic types: int and int \(if/else branches differ only by a constant; did you mean to compare the values\?\)`
+		return "same"
+	} else {
+		return "different"
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package importalias
+
+import (
+	. "importalias/units"
+	u "importalias/units"
+	clock "time"
+)
+
+func compare(a, b *u.Meters, c, d *Meters, e, f *u.Seconds, g, h *Seconds, i, j *clock.Duration) {
+	_ = a == b
+	_ = c == d
+	_ = a == c
+	_ = e == f // want `comparing pointers to basic types: importalias/units\.Seconds and importalias/units\.Seconds`
+	_ = g == h // want `comparing pointers to basic types: importalias/units\.Seconds and importalias/units\.Seconds`
+	_ = i == j
+}
@@ -0,0 +1,40 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package initstmts
+
+func get() (*int, *int) { return nil, nil }
+
+func next(p *string) *string { return p }
+
+func conditions(s *string) {
+	if p, q := get(); p == q { // want `comparing pointers to basic types: int and int`
+	}
+	if p, q := get(); p != nil && *p == *q {
+	} else if r, t := get(); r == t { // want `comparing pointers to basic types: int and int`
+	}
+	for p := s; p != next(p); p = next(p) { // want `comparing pointers to basic types: string and string`
+	}
+	switch p, q := get(); {
+	case p == q: // want `comparing pointers to basic types: int and int`
+	}
+	if p := s; p == s { // want `comparing pointers to basic types: string and string`
+		p := 1
+		_ = p == 1
+	}
+}
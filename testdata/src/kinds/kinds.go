@@ -0,0 +1,25 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package kinds
+
+func lowLevel(u, v *uintptr, b *byte, c *uint8, r *rune, i *int32) {
+	_ = u == v // want `comparing pointers to basic types: uintptr and uintptr`
+	_ = b == c // want `comparing pointers to basic types: byte and uint8`
+	_ = r != i // want `comparing pointers to basic types: rune and int32`
+}
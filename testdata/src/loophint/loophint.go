@@ -0,0 +1,67 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package loophint
+
+type registry struct {
+	names []*string
+}
+
+func find(ptrs []*int, target *int) int {
+	for i, p := range ptrs {
+		if p == target { // want `comparing pointers to basic types: int and int \(searching a slice of pointers finds the same pointer, not an equal value; did you mean to compare the values\?\)`
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(r *registry, arr *[3]*string, name *string) bool {
+	for i := range r.names {
+		if name == r.names[i] { // want `comparing pointers to basic types: string and string \(searching a slice of pointers finds the same pointer, not an equal value; did you mean to compare the values\?\)`
+			return true
+		}
+	}
+	for _, n := range arr {
+		if n != nil && (n) != name { // want `comparing pointers to basic types: string and string \(searching`
+			continue
+		}
+	}
+	return false
+}
+
+func notSearches(ptrs []*int, values []int, target *int, m map[string]*int) {
+	for _, p := range ptrs {
+		_ = p == p               // want `comparing a pointer to itself; always true$`
+		_ = target == &values[0] // want `comparing pointers to basic types: int and int$`
+		_ = *p == *target
+		go func() {
+			_ = p == target // want `comparing pointers to basic types: int and int$`
+		}()
+	}
+	for i, v := range values {
+		_ = &v == target         // want `comparing pointers to basic types: int and int$`
+		_ = &values[i] == target // want `comparing pointers to basic types: int and int$`
+	}
+	for _, p := range m {
+		_ = p == target // want `comparing pointers to basic types: int and int$`
+	}
+	for i := 0; i < len(ptrs); i++ {
+		_ = ptrs[i] == target // want `comparing pointers to basic types: int and int$`
+	}
+}
@@ -0,0 +1,37 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package messages
+
+import "unsafe"
+
+func every(p, q *int, s *string) int {
+	_ = p == q                                                   // want `comparing pointers to basic types: int and int`
+	_ = p != nil                                                 // want `comparing pointer to basic type with nil: int`
+	_ = nil == s                                                 // want `comparing pointer to basic type with nil: string`
+	_ = p == new(int)                                            // want `comparing against a freshly allocated pointer to int from new; always false`
+	_ = p == p                                                   // want `comparing a pointer to itself; always true`
+	_ = uintptr(unsafe.Pointer(p)) == uintptr(unsafe.Pointer(q)) // want `comparing addresses of pointers to basic types through uintptr: int and int`
+	_ = (*int)(unsafe.Add(unsafe.Pointer(p), 8)) == q            // want `comparing pointers to basic types computed with unsafe pointer arithmetic: int and int`
+	_ = any(p) == any(q)                                         // want `comparing interfaces holding pointers to basic types: int and int`
+	if p == q {                                                  // want `comparing pointers to basic types: int and int \(if/else branches differ only by a constant; did you mean to compare the values\?\)`
+		return 1
+	} else {
+		return 2
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package namedtypes
+
+type Temperature float64
+
+func (t Temperature) Celsius() float64 { return float64(t) }
+
+func (t *Temperature) Set(v float64) { *t = Temperature(v) }
+
+type Point struct{ X, Y int }
+
+func (p Point) Sum() int { return p.X + p.Y }
+
+type MyInt int
+
+type Count MyInt
+
+func compare(a, b *Temperature, c, d *Point) {
+	_ = a == b // want `comparing pointers to basic types: namedtypes\.Temperature and namedtypes\.Temperature`
+	_ = c == d
+}
+
+// bridge compares pointers to named and unnamed basic types made comparable by
+// converting one side, which compares two pointers to the converted type.
+func bridge(i *int, m *MyInt, n *Count, f *float64) {
+	_ = i == (*int)(m)         // want `comparing pointers to basic types: int and int`
+	_ = (*MyInt)(i) != m       // want `comparing pointers to basic types: namedtypes\.MyInt and namedtypes\.MyInt`
+	_ = (*int)(n) == (*int)(m) // want `comparing pointers to basic types: int and int`
+	_ = (*Count)(m) == n       // want `comparing pointers to basic types: namedtypes\.Count and namedtypes\.Count`
+	_ = (*Temperature)(f) == nil
+	_ = *i == int(*m)
+}
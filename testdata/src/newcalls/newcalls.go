@@ -0,0 +1,54 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package newcalls
+
+type MyInt int
+
+func compare(p *int, m *MyInt, s *struct{}) {
+	_ = p == new(int)     // want `comparing against a freshly allocated pointer to int from new; always false`
+	_ = new(int) != p     // want `comparing against a freshly allocated pointer to int from new; always true`
+	_ = m == (new(MyInt)) // want `comparing against a freshly allocated pointer to newcalls\.MyInt from new; always false`
+	_ = s == new(struct{})
+}
+
+func variables(p *int) {
+	a, b := new(int), new(int)
+	var c = new(int)
+	_ = a != b   // want `comparing against a freshly allocated pointer to int from new; always true`
+	_ = (c) == a // want `comparing against a freshly allocated pointer to int from new; always false`
+	_ = a == p   // want `comparing pointers to basic types: int and int`
+
+	d := new(int)
+	d = p
+	e := new(int)
+	_ = &e
+	f := a
+	_ = d == a // want `comparing pointers to basic types: int and int`
+	_ = e == a // want `comparing pointers to basic types: int and int`
+	_ = f == b // want `comparing pointers to basic types: int and int`
+
+	g := new(int)
+	func() { g = p }()
+	_ = g == a // want `comparing pointers to basic types: int and int`
+}
+
+func shadowed(p *int) {
+	new := func() *int { return p }
+	_ = p == new() // want `comparing pointers to basic types: int and int`
+}
@@ -0,0 +1,54 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package nilscope
+
+type Cache[K comparable] struct {
+	hits *int
+}
+
+func NewCache[K comparable](hits *int) *Cache[K] {
+	if hits == nil { // want `comparing pointer to basic type with nil: int`
+		panic("nil hits")
+	}
+	return &Cache[K]{hits: hits}
+}
+
+func (c *Cache[K]) Get(key K) bool {
+	return c.hits != nil // want `comparing pointer to basic type with nil: int`
+}
+
+func (c *Cache[K]) Set(key K) bool {
+	return c.hits != nil
+}
+
+func must(p *string) string {
+	check := func() bool {
+		return p == nil // want `comparing pointer to basic type with nil: string`
+	}
+	if check() {
+		panic("nil")
+	}
+	return *p
+}
+
+func lookup(p *string) bool {
+	return p == nil
+}
+
+var isNil = func(p *int) bool { return p == nil }
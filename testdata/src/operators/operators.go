@@ -0,0 +1,31 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package operators
+
+import "unsafe"
+
+func compare(p, q *int) {
+	_ = p == q                                                   // want `comparing pointers to basic types with '==': int and int`
+	_ = p != q                                                   // want `comparing pointers to basic types with '!=': int and int`
+	_ = p != nil                                                 // want `comparing pointer to basic type with nil using '!=': int`
+	_ = p == p                                                   // want `comparing a pointer to itself with '=='; always true`
+	_ = p != new(int)                                            // want `comparing with '!=' against a freshly allocated pointer to int from new; always true`
+	_ = uintptr(unsafe.Pointer(p)) >= uintptr(unsafe.Pointer(q)) // want `comparing addresses of pointers to basic types through uintptr with '>=': int and int`
+	_ = any(p) == any(q)                                         // want `comparing interfaces holding pointers to basic types with '==': int and int`
+}
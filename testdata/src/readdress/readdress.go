@@ -0,0 +1,35 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package readdress
+
+type pair struct{ a, b *int }
+
+func compare(p, q *int, pp **int, t pair, x, y int, s *pair) {
+	_ = &*p == &*q       // want `comparing pointers to basic types: int and int`
+	_ = &(*p) != &(*(q)) // want `comparing pointers to basic types: int and int`
+	_ = &**pp == q       // want `comparing pointers to basic types: int and int`
+	_ = &*&*p == p       // want `comparing a pointer to itself; always true`
+	_ = &*t.a != t.a     // want `comparing a pointer to itself; always false`
+	_ = &*t.a == &*t.b   // want `comparing pointers to basic types: int and int`
+	_ = &*&x == &y       // want `comparing pointers to basic types: int and int`
+	_ = *&*p == *&*q
+	_ = *&x == y
+	_ = &*s == s
+	_ = &*pp == &*pp
+}
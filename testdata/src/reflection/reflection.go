@@ -0,0 +1,43 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package reflection
+
+import "reflect"
+
+func compare(x, y int, p *int, v, w reflect.Value) {
+	rx, ry := reflect.ValueOf(&x).Elem(), reflect.ValueOf(&y).Elem()
+	_ = rx == ry
+	_ = rx.Addr() == ry.Addr()
+	_ = reflect.ValueOf(&x).Elem().Addr() == reflect.ValueOf(&y).Elem().Addr()
+	_ = rx.Addr().Pointer() == ry.Addr().Pointer()
+	_ = rx.Addr().UnsafePointer() == ry.Addr().UnsafePointer()
+	_ = rx.Addr().Interface() == ry.Addr().Interface()
+	_ = rx.Type() == reflect.TypeOf(p).Elem()
+	_ = v.Kind() == reflect.Pointer && w.Kind() == reflect.Pointer
+	_ = v.Elem().Field(0).Addr() == w.Elem().FieldByName("N").Addr()
+	_ = reflect.Indirect(v) == reflect.Indirect(w)
+
+	q := rx.Addr().Interface().(*int)
+	_ = q == p // want `comparing pointers to basic types: int and int`
+	if r, ok := ry.Addr().Interface().(*int); ok && r == p { // want `comparing pointers to basic types: int and int`
+		return
+	}
+	_ = rx.Addr().Interface().(*int) == reflect.ValueOf(p).Interface().(*int) // want `comparing pointers to basic types: int and int`
+	_ = *rx.Addr().Interface().(*int) == *p
+}
@@ -0,0 +1,31 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package related
+
+var global *int
+
+func locals(param *int) {
+	var one *int
+	two := new(int)
+
+	_ = one == two                            // want `comparing pointers to basic types: int and int`
+	_ = param != global                       // want `comparing pointers to basic types: int and int`
+	_ = one == one                            // want `comparing a pointer to itself; always true`
+	_ = (one) == func() *int { return two }() // want `comparing pointers to basic types: int and int`
+}
@@ -0,0 +1,40 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package samebase
+
+type table struct {
+	rows []*int
+}
+
+func compare(s, other []*int, arr [4]*string, parr *[4]*string, m map[string]*int, t table, i, j int, k string) {
+	for i := range s {
+		for j := i + 1; j < len(s); j++ {
+			if s[i] == s[j] { // want `comparing pointers to basic types from the same slice s: int and int; did you mean \*s\[i\] == \*s\[j\]\?`
+				return
+			}
+		}
+	}
+	_ = arr[i] != arr[j]       // want `comparing pointers to basic types from the same array arr: string and string; did you mean \*arr\[i\] != \*arr\[j\]\?`
+	_ = parr[i] == parr[0]     // want `comparing pointers to basic types from the same array parr: string and string; did you mean \*parr\[i\] == \*parr\[0\]\?`
+	_ = m[k] == m["a"]         // want `comparing pointers to basic types from the same map m: int and int; did you mean \*m\[k\] == \*m\["a"\]\?`
+	_ = t.rows[i] == t.rows[j] // want `comparing pointers to basic types from the same slice t\.rows: int and int; did you mean \*t\.rows\[i\] == \*t\.rows\[j\]\?`
+	_ = s[i] == other[j]       // want `comparing pointers to basic types: int and int`
+	_ = s[0] == s[1]           // want `comparing pointers to basic types: int and int`
+	_ = *s[i] == *s[j]
+}
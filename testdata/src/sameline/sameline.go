@@ -0,0 +1,29 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package sameline
+
+func compare(a, b, c, d *int, s, t *string) bool {
+	if a == b && c == d { // want `int and int` `int and int`
+		return true
+	}
+	if a != c || s == t || b == d { // want `int and int` `string and string` `int and int`
+		return false
+	}
+	return s != t // want `string and string`
+}
@@ -0,0 +1,41 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package selfcompare
+
+type pair struct {
+	x, y *int
+}
+
+type outer struct {
+	inner pair
+}
+
+func next() *int { return new(int) }
+
+func compare(p, q *int, a, b pair, o outer, ps []*int) {
+	_ = p == p                 // want `comparing a pointer to itself; always true`
+	_ = p != (p)               // want `comparing a pointer to itself; always false`
+	_ = a.x == a.x             // want `comparing a pointer to itself; always true`
+	_ = o.inner.y == o.inner.y // want `comparing a pointer to itself; always true`
+	_ = p == q                 // want `comparing pointers to basic types: int and int`
+	_ = a.x == b.x             // want `comparing pointers to basic types: int and int`
+	_ = a.x == a.y             // want `comparing pointers to basic types: int and int`
+	_ = next() == next()       // want `comparing pointers to basic types: int and int`
+	_ = ps[0] == ps[0]         // want `comparing pointers to basic types: int and int`
+}
@@ -0,0 +1,52 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package tuples
+
+type record struct {
+	ID    int
+	Name  *string
+	Count *int
+}
+
+func load() (*int, string, *string) { return nil, "", nil }
+
+func fields(r record) (int, *string, *int) { return r.ID, r.Name, r.Count }
+
+func compare() bool {
+	a1, a2, a3 := load()
+	b1, b2, b3 := load()
+	if a1 == b1 && a2 == b2 && a3 != b3 { // want `comparing pointers to basic types: int and int` `comparing pointers to basic types: string and string`
+		return true
+	}
+	return *a1 == *b1 && a2 == b2 && *a3 == *b3
+}
+
+// equal is the usual field by field comparison after a struct grew pointer
+// fields: only the pointer fields are reported, each on its own.
+func equal(x, y record) bool {
+	xID, xName, xCount := fields(x)
+	yID, yName, yCount := fields(y)
+	return xID == yID && // value comparison
+		xName == yName && // want `comparing pointers to basic types: string and string`
+		xCount == yCount // want `comparing pointers to basic types: int and int`
+}
+
+func equalFields(x, y record) bool {
+	return x.ID == y.ID && x.Name == y.Name && x.Count == y.Count // want `comparing pointers to basic types: string and string` `comparing pointers to basic types: int and int`
+}
@@ -0,0 +1,33 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package typednil
+
+type MyType int
+
+type Ptr *MyType
+
+func compare(p *int, m *MyType, mp Ptr, q **int) {
+	_ = p == (*int)(nil)      // want `comparing pointer to basic type with nil: int`
+	_ = (*MyType)(nil) != m   // want `comparing pointer to basic type with nil: typednil\.MyType`
+	_ = m == ((*MyType)(nil)) // want `comparing pointer to basic type with nil: typednil\.MyType`
+	_ = mp == Ptr(nil)
+	_ = q == (**int)(nil)
+	_ = *q == (*int)(nil)     // want `comparing pointer to basic type with nil: int`
+	_ = p == (*int)(new(int)) // want `comparing pointers to basic types: int and int`
+}
@@ -0,0 +1,45 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package typeswitch
+
+func compare(x, y any, other *int, name *string) bool {
+	switch v := x.(type) {
+	case *int:
+		if v == other { // want `comparing pointers to basic types: int and int`
+			return true
+		}
+		switch w := y.(type) {
+		case *int:
+			return w != v // want `comparing pointers to basic types: int and int`
+		}
+	case *string:
+		return name == v // want `comparing pointers to basic types: string and string`
+	case *int64, *float64:
+		return v == x
+	case nil:
+		return v == nil
+	default:
+		return v == y
+	}
+	switch v := (x).(type) {
+	case **int:
+		return *v == other // want `comparing pointers to basic types: int and int`
+	}
+	return false
+}
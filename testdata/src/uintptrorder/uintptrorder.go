@@ -0,0 +1,27 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package uintptrorder
+
+import "unsafe"
+
+// lockOrder orders two locks by address, which is valid and sometimes
+// intended, so it is only reported by the ordered rule.
+func lockOrder(p, q *int) bool {
+	return uintptr(unsafe.Pointer(p)) < uintptr(unsafe.Pointer(q)) // want `comparing addresses of pointers to basic types through uintptr: int and int`
+}
@@ -0,0 +1,42 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package uintptrs
+
+import "unsafe"
+
+type Point struct{ X, Y int }
+
+func compare(p, q *int, a, b *Point, u, v uintptr) {
+	_ = uintptr(unsafe.Pointer(p)) == uintptr(unsafe.Pointer(q))     // want `comparing addresses of pointers to basic types through uintptr: int and int`
+	_ = (uintptr)(unsafe.Pointer(p)) != uintptr((unsafe.Pointer)(q)) // want `comparing addresses of pointers to basic types through uintptr: int and int`
+	_ = uintptr(unsafe.Pointer(a)) == uintptr(unsafe.Pointer(b))
+	_ = uintptr(unsafe.Pointer(p)) == u
+	_ = u == v
+}
+
+func arithmetic(p, q *int, s *string, a *Point, buf *[4]int) {
+	_ = (*int)(unsafe.Add(unsafe.Pointer(p), 8)) == q                       // want `comparing pointers to basic types computed with unsafe pointer arithmetic: int and int`
+	_ = q != (*int)(unsafe.Add(unsafe.Pointer(buf), unsafe.Sizeof(buf[0]))) // want `comparing pointers to basic types computed with unsafe pointer arithmetic: int and int`
+	_ = (*int)(unsafe.Pointer(uintptr(unsafe.Pointer(p))+8)) == q           // want `comparing pointers to basic types computed with unsafe pointer arithmetic: int and int`
+	_ = (*string)((unsafe.Add((unsafe.Pointer)(s), -16))) == s              // want `comparing pointers to basic types computed with unsafe pointer arithmetic: string and string`
+	_ = (*Point)(unsafe.Add(unsafe.Pointer(a), 8)) == a
+	_ = (*int)(unsafe.Add(unsafe.Pointer(a), unsafe.Offsetof(a.Y))) == &a.Y // want `comparing pointers to basic types computed with unsafe pointer arithmetic: int and int`
+	_ = unsafe.Add(unsafe.Pointer(p), 8) == unsafe.Pointer(q)
+	_ = *(*int)(unsafe.Add(unsafe.Pointer(p), 8)) == *q
+}
@@ -0,0 +1,43 @@
+/*
+ptrcmp
+Copyright (C) 2025  loveholidays
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU Lesser General Public
+License as published by the Free Software Foundation; either
+version 3 of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program; if not, write to the Free Software Foundation,
+Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+*/
+package variadic
+
+import "fmt"
+
+func results(ok ...bool) {}
+
+func arguments(a, b, c, d *int, s *string) {
+	fmt.Println(a == b, c != d)       // want `comparing pointers to basic types: int and int` `comparing pointers to basic types: int and int`
+	results(a == c, s == s, *a == *b) // want `comparing pointers to basic types: int and int` `comparing a pointer to itself; always true`
+	results([]bool{a == d}...)        // want `comparing pointers to basic types: int and int`
+}
+
+func tagless(p, q *int, s, t *string) int {
+	switch {
+	case p == q: // want `comparing pointers to basic types: int and int`
+		return 1
+	case *p == *q, s != t: // want `comparing pointers to basic types: string and string`
+		return 2
+	}
+	switch p == q { // want `comparing pointers to basic types: int and int`
+	case s == t: // want `comparing pointers to basic types: string and string`
+		return 3
+	}
+	return 0
+}